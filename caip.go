@@ -0,0 +1,251 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// CAIP2NamespaceEIP155 is the CAIP-2 namespace of EVM compatible blockchains.
+// All blockchains known to this package belong to this namespace.
+const CAIP2NamespaceEIP155 = "eip155"
+
+var (
+	// ErrInvalidCAIP2ChainID returned when string is not a valid CAIP-2 chain ID.
+	ErrInvalidCAIP2ChainID = errors.New("invalid CAIP-2 chain ID")
+	// ErrInvalidCAIP10AccountID returned when string is not a valid CAIP-10
+	// account ID.
+	ErrInvalidCAIP10AccountID = errors.New("invalid CAIP-10 account ID")
+	// ErrUnsupportedCAIPNamespace returned when CAIP namespace can't be mapped
+	// to the blockchain known to this package.
+	ErrUnsupportedCAIPNamespace = errors.New("unsupported CAIP namespace")
+)
+
+// https://github.com/ChainAgnostic/CAIPs/blob/main/CAIPs/caip-2.md
+// https://github.com/ChainAgnostic/CAIPs/blob/main/CAIPs/caip-10.md
+var (
+	caip2NamespaceRe    = regexp.MustCompile(`^[-a-z0-9]{3,8}$`)
+	caip2ReferenceRe    = regexp.MustCompile(`^[-_a-zA-Z0-9]{1,32}$`)
+	caip10AccountAddrRe = regexp.MustCompile(`^[-.%a-zA-Z0-9]{1,128}$`)
+	// eip155 reference is a decimal chain ID without sign and leading zeros
+	eip155ReferenceRe = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+)
+
+// CAIP2ChainID is a blockchain identifier as described in CAIP-2, e.g.
+// eip155:137
+type CAIP2ChainID struct {
+	Namespace string
+	Reference string
+}
+
+// String returns CAIP-2 string representation of chain ID
+func (c CAIP2ChainID) String() string {
+	return c.Namespace + ":" + c.Reference
+}
+
+// MarshalText returns CAIP-2 string representation of chain ID
+func (c CAIP2ChainID) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText parses CAIP-2 chain ID
+func (c *CAIP2ChainID) UnmarshalText(b []byte) error {
+	c2, err := ParseCAIP2ChainID(string(b))
+	if err != nil {
+		return err
+	}
+	*c = c2
+	return nil
+}
+
+// ChainID returns the EVM chain ID of the CAIP-2 chain ID. Only eip155
+// namespace is supported.
+func (c CAIP2ChainID) ChainID() (ChainID, error) {
+	if c.Namespace != CAIP2NamespaceEIP155 {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCAIPNamespace,
+			c.Namespace)
+	}
+	if !eip155ReferenceRe.MatchString(c.Reference) {
+		return 0, fmt.Errorf("%w: invalid eip155 reference: %s",
+			ErrInvalidCAIP2ChainID, c.Reference)
+	}
+	chainID, err := strconv.ParseInt(c.Reference, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid eip155 reference: %s",
+			ErrInvalidCAIP2ChainID, c.Reference)
+	}
+	return ChainID(chainID), nil
+}
+
+// ParseCAIP2ChainID parses CAIP-2 chain ID string
+func ParseCAIP2ChainID(s string) (CAIP2ChainID, error) {
+	ns, ref, ok := strings.Cut(s, ":")
+	if !ok || !caip2NamespaceRe.MatchString(ns) ||
+		!caip2ReferenceRe.MatchString(ref) {
+
+		return CAIP2ChainID{}, fmt.Errorf("%w: %s", ErrInvalidCAIP2ChainID, s)
+	}
+	return CAIP2ChainID{Namespace: ns, Reference: ref}, nil
+}
+
+// CAIP2ChainIDFromChainID returns CAIP-2 chain ID of EVM chain.
+func CAIP2ChainIDFromChainID(chainID ChainID) CAIP2ChainID {
+	return CAIP2ChainID{
+		Namespace: CAIP2NamespaceEIP155,
+		Reference: strconv.FormatInt(int64(chainID), 10),
+	}
+}
+
+// CAIP2ChainIDFromID returns CAIP-2 chain ID of the blockchain and network
+// encoded in ID type.
+func CAIP2ChainIDFromID(id ID) (CAIP2ChainID, error) {
+	chainID, err := ChainIDfromID(id)
+	if err != nil {
+		return CAIP2ChainID{}, err
+	}
+	return CAIP2ChainIDFromChainID(chainID), nil
+}
+
+// CAIP2ChainIDFromDID returns CAIP-2 chain ID of the blockchain and network
+// of the DID.
+func CAIP2ChainIDFromDID(did w3c.DID) (CAIP2ChainID, error) {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return CAIP2ChainID{}, err
+	}
+	return CAIP2ChainIDFromID(id)
+}
+
+// CAIP10AccountID is an account identifier as described in CAIP-10, e.g.
+// eip155:137:0xab16a96d359ec26a11e2c2b3d8f8b8942d5bfcdb
+type CAIP10AccountID struct {
+	ChainID CAIP2ChainID
+	Address string
+}
+
+// String returns CAIP-10 string representation of account ID
+func (a CAIP10AccountID) String() string {
+	return a.ChainID.String() + ":" + a.Address
+}
+
+// MarshalText returns CAIP-10 string representation of account ID
+func (a CAIP10AccountID) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText parses CAIP-10 account ID
+func (a *CAIP10AccountID) UnmarshalText(b []byte) error {
+	a2, err := ParseCAIP10AccountID(string(b))
+	if err != nil {
+		return err
+	}
+	*a = a2
+	return nil
+}
+
+// EthAddress returns Ethereum address of the account. Only eip155 namespace
+// is supported.
+func (a CAIP10AccountID) EthAddress() ([20]byte, error) {
+	if a.ChainID.Namespace != CAIP2NamespaceEIP155 {
+		return [20]byte{}, fmt.Errorf("%w: %s", ErrUnsupportedCAIPNamespace,
+			a.ChainID.Namespace)
+	}
+	return EthAddressFromHex(a.Address)
+}
+
+// ParseCAIP10AccountID parses CAIP-10 account ID string
+func ParseCAIP10AccountID(s string) (CAIP10AccountID, error) {
+	idx := strings.LastIndexByte(s, ':')
+	if idx == -1 {
+		return CAIP10AccountID{}, fmt.Errorf("%w: %s",
+			ErrInvalidCAIP10AccountID, s)
+	}
+
+	chainID, err := ParseCAIP2ChainID(s[:idx])
+	if err != nil {
+		return CAIP10AccountID{}, fmt.Errorf("%w: %s",
+			ErrInvalidCAIP10AccountID, s)
+	}
+
+	addr := s[idx+1:]
+	if !caip10AccountAddrRe.MatchString(addr) {
+		return CAIP10AccountID{}, fmt.Errorf("%w: %s",
+			ErrInvalidCAIP10AccountID, s)
+	}
+
+	return CAIP10AccountID{ChainID: chainID, Address: addr}, nil
+}
+
+// CAIP10AccountIDFromID returns CAIP-10 account ID of Ethereum-controlled
+// identity. Returns error if ID genesis is not an Ethereum address.
+func CAIP10AccountIDFromID(id ID) (CAIP10AccountID, error) {
+	addr, err := EthAddressFromID(id)
+	if err != nil {
+		return CAIP10AccountID{}, err
+	}
+
+	chainID, err := CAIP2ChainIDFromID(id)
+	if err != nil {
+		return CAIP10AccountID{}, err
+	}
+
+	return CAIP10AccountID{
+		ChainID: chainID,
+		Address: EthAddressToChecksumHex(addr),
+	}, nil
+}
+
+// CAIP10AccountIDFromDID returns CAIP-10 account ID of Ethereum-controlled
+// identity DID.
+func CAIP10AccountIDFromDID(did w3c.DID) (CAIP10AccountID, error) {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return CAIP10AccountID{}, err
+	}
+	return CAIP10AccountIDFromID(id)
+}
+
+// NewIDFromCAIP10AccountID creates Ethereum-controlled ID of the given DID
+// method from CAIP-10 account ID. Blockchain and network of ID type are
+// looked up by the chain ID of the account.
+func NewIDFromCAIP10AccountID(method DIDMethod,
+	account CAIP10AccountID) (ID, error) {
+
+	addr, err := account.EthAddress()
+	if err != nil {
+		return ID{}, err
+	}
+
+	chainID, err := account.ChainID.ChainID()
+	if err != nil {
+		return ID{}, err
+	}
+
+	blockchain, networkID, err := NetworkByChainID(chainID)
+	if err != nil {
+		return ID{}, err
+	}
+
+	typ, err := BuildDIDType(method, blockchain, networkID)
+	if err != nil {
+		return ID{}, err
+	}
+
+	return NewID(typ, GenesisFromEthAddress(addr)), nil
+}
+
+// NewDIDFromCAIP10AccountID creates Ethereum-controlled DID of the given DID
+// method from CAIP-10 account ID.
+func NewDIDFromCAIP10AccountID(method DIDMethod,
+	account CAIP10AccountID) (*w3c.DID, error) {
+
+	id, err := NewIDFromCAIP10AccountID(method, account)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDIDFromID(id)
+}
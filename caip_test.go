@@ -0,0 +1,137 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCAIP2ChainID(t *testing.T) {
+	c, err := ParseCAIP2ChainID("eip155:137")
+	require.NoError(t, err)
+	require.Equal(t, CAIP2ChainID{Namespace: "eip155", Reference: "137"}, c)
+	require.Equal(t, "eip155:137", c.String())
+
+	chainID, err := c.ChainID()
+	require.NoError(t, err)
+	require.Equal(t, ChainID(137), chainID)
+
+	c, err = ParseCAIP2ChainID("bip122:000000000019d6689c085ae165831e93")
+	require.NoError(t, err)
+	_, err = c.ChainID()
+	require.ErrorIs(t, err, ErrUnsupportedCAIPNamespace)
+
+	for _, in := range []string{"", "eip155", "eip155:", ":137", "EIP155:1",
+		"ei:1", "eip155:1:2"} {
+		_, err = ParseCAIP2ChainID(in)
+		require.ErrorIs(t, err, ErrInvalidCAIP2ChainID, in)
+	}
+
+	c, err = ParseCAIP2ChainID("eip155:0")
+	require.NoError(t, err)
+	chainID, err = c.ChainID()
+	require.NoError(t, err)
+	require.Equal(t, ChainID(0), chainID)
+
+	// non-canonical references of the same chain ID
+	for _, ref := range []string{"-1", "+1", "01", "00", "-0", "1_0",
+		"99999999999"} {
+
+		c = CAIP2ChainID{Namespace: CAIP2NamespaceEIP155, Reference: ref}
+		_, err = c.ChainID()
+		require.ErrorIs(t, err, ErrInvalidCAIP2ChainID, ref)
+	}
+}
+
+func TestParseCAIP10AccountID(t *testing.T) {
+	a, err := ParseCAIP10AccountID(
+		"eip155:80001:0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0")
+	require.NoError(t, err)
+	require.Equal(t, CAIP2ChainID{Namespace: "eip155", Reference: "80001"},
+		a.ChainID)
+	require.Equal(t, "0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0", a.Address)
+
+	addr, err := a.EthAddress()
+	require.NoError(t, err)
+	require.Equal(t, ethAddrFromHex("a51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0"),
+		addr)
+
+	for _, in := range []string{"", "eip155:1", "eip155:1:", "eip155:1:0x$1"} {
+		_, err = ParseCAIP10AccountID(in)
+		require.ErrorIs(t, err, ErrInvalidCAIP10AccountID, in)
+	}
+}
+
+func TestCAIP2ChainIDFromID(t *testing.T) {
+	id, err := IDFromString("2qCU58EJgrEM9NKvHkvg5NFWUiJPgN3M3LnCr98j3x")
+	require.NoError(t, err)
+
+	c, err := CAIP2ChainIDFromID(id)
+	require.NoError(t, err)
+	require.Equal(t, "eip155:80001", c.String())
+
+	did := helperBuildDIDFromType(t, DIDMethodIden3, Polygon, Amoy)
+	c, err = CAIP2ChainIDFromDID(*did)
+	require.NoError(t, err)
+	require.Equal(t, "eip155:80002", c.String())
+}
+
+func TestCAIP10AccountIDFromID(t *testing.T) {
+	id, err := IDFromString("2qCU58EJgrEM9NKvHkvg5NFWUiJPgN3M3LnCr98j3x")
+	require.NoError(t, err)
+
+	a, err := CAIP10AccountIDFromID(id)
+	require.NoError(t, err)
+	require.Equal(t, "eip155:80001:0xA51c1fc2f0D1a1b8494Ed1FE312d7C3a78Ed91C0",
+		a.String())
+
+	did, err := ParseDIDFromID(id)
+	require.NoError(t, err)
+	a2, err := CAIP10AccountIDFromDID(*did)
+	require.NoError(t, err)
+	require.Equal(t, a, a2)
+
+	// genesis is not an Ethereum address
+	id, err = IDFromString("wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+	_, err = CAIP10AccountIDFromID(id)
+	require.EqualError(t, err,
+		"can't get Ethereum address: high bytes of genesis are not zero")
+}
+
+func TestNewIDFromCAIP10AccountID(t *testing.T) {
+	a, err := ParseCAIP10AccountID(
+		"eip155:80001:0xA51c1fc2f0D1a1b8494Ed1FE312d7C3a78Ed91C0")
+	require.NoError(t, err)
+
+	id, err := NewIDFromCAIP10AccountID(DIDMethodPolygonID, a)
+	require.NoError(t, err)
+	require.Equal(t, "2qCU58EJgrEM9NKvHkvg5NFWUiJPgN3M3LnCr98j3x", id.String())
+
+	did, err := NewDIDFromCAIP10AccountID(DIDMethodPolygonID, a)
+	require.NoError(t, err)
+	require.Equal(t,
+		"did:polygonid:polygon:mumbai:2qCU58EJgrEM9NKvHkvg5NFWUiJPgN3M3LnCr98j3x",
+		did.String())
+
+	a.ChainID.Reference = "999999"
+	_, err = NewIDFromCAIP10AccountID(DIDMethodPolygonID, a)
+	require.True(t, errors.Is(err, ErrChainIDNotRegistered))
+}
+
+func TestCAIP10AccountID_JSON(t *testing.T) {
+	a, err := ParseCAIP10AccountID(
+		"eip155:1:0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0")
+	require.NoError(t, err)
+
+	b, err := json.Marshal(a)
+	require.NoError(t, err)
+	require.Equal(t,
+		`"eip155:1:0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0"`, string(b))
+
+	var a2 CAIP10AccountID
+	require.NoError(t, json.Unmarshal(b, &a2))
+	require.Equal(t, a, a2)
+}
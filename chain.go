@@ -49,7 +49,13 @@ func ChainIDfromDID(did w3c.DID) (ChainID, error) {
 }
 
 // ChainIDfromID(id ID) returns chain name from ID
+// Returns ErrReadOnlyIdentity for readonly IDs.
 func ChainIDfromID(id ID) (ChainID, error) {
+	if IsReadOnlyID(id) {
+		return 0, fmt.Errorf("%w: %v is not bound to any chain",
+			ErrReadOnlyIdentity, id.String())
+	}
+
 	blockchain, err := BlockchainFromID(id)
 	if err != nil {
 		return 0, err
@@ -1,7 +1,6 @@
 package core
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -318,23 +317,24 @@ func RegisterDIDMethodNetwork(params DIDMethodNetworkParams, opts ...Registratio
 
 // BuildDIDType builds bytes type from chain and network
 func BuildDIDType(method DIDMethod, blockchain Blockchain,
-	network NetworkID) ([2]byte, error) {
+	network NetworkID) (DIDType, error) {
 
 	fb, ok := DIDMethodByte[method]
 	if !ok {
-		return [2]byte{}, ErrDIDMethodNotSupported
+		return DIDType{}, ErrDIDMethodNotSupported
 	}
 
 	netFlag := DIDNetworkFlag{Blockchain: blockchain, NetworkID: network}
 	sb, ok := DIDMethodNetwork[method][netFlag]
 	if !ok {
-		return [2]byte{}, ErrNetworkNotSupportedForDID
+		return DIDType{}, ErrNetworkNotSupportedForDID
 	}
 
-	return [2]byte{fb, sb}, nil
+	return DIDType{fb, sb}, nil
 }
 
-// FindNetworkIDForDIDMethodByValue finds network by byte value
+// FindNetworkIDForDIDMethodByValue finds network by the second byte of
+// DIDType. Use DIDType.Network to get the network of the type.
 func FindNetworkIDForDIDMethodByValue(method DIDMethod, _v byte) (NetworkID, error) {
 	_, ok := DIDMethodNetwork[method]
 	if !ok {
@@ -348,7 +348,8 @@ func FindNetworkIDForDIDMethodByValue(method DIDMethod, _v byte) (NetworkID, err
 	return UnknownNetwork, ErrNetworkNotSupportedForDID
 }
 
-// FindBlockchainForDIDMethodByValue finds blockchain type by byte value
+// FindBlockchainForDIDMethodByValue finds blockchain by the second byte of
+// DIDType. Use DIDType.Blockchain to get the blockchain of the type.
 func FindBlockchainForDIDMethodByValue(method DIDMethod, _v byte) (Blockchain, error) {
 	_, ok := DIDMethodNetwork[method]
 	if !ok {
@@ -362,11 +363,16 @@ func FindBlockchainForDIDMethodByValue(method DIDMethod, _v byte) (Blockchain, e
 	return UnknownChain, ErrBlockchainNotSupportedForDID
 }
 
-// FindDIDMethodByValue finds did method by its byte value
+// FindDIDMethodByValue finds did method by its byte value. Values with the
+// version 2 ID flag set resolve to the method of the value without the flag.
 func FindDIDMethodByValue(b byte) (DIDMethod, error) {
-	for k, v := range DIDMethodByte {
-		if v == b {
-			return k, nil
+	if method, ok := findDIDMethodByExactValue(b); ok {
+		return method, nil
+	}
+	if b&idVersion2Flag != 0 {
+		method, ok := findDIDMethodByExactValue(b &^ idVersion2Flag)
+		if ok && method != DIDMethodOther {
+			return method, nil
 		}
 	}
 	return DIDMethodOther, ErrDIDMethodNotSupported
@@ -374,7 +380,7 @@ func FindDIDMethodByValue(b byte) (DIDMethod, error) {
 
 // NewDIDFromIdenState calculates the genesis ID from an Identity State and
 // returns it as a DID
-func NewDIDFromIdenState(typ [2]byte, state *big.Int) (*w3c.DID, error) {
+func NewDIDFromIdenState(typ DIDType, state *big.Int) (*w3c.DID, error) {
 	id, err := NewIDFromIdenState(typ, state)
 	if err != nil {
 		return nil, err
@@ -383,24 +389,31 @@ func NewDIDFromIdenState(typ [2]byte, state *big.Int) (*w3c.DID, error) {
 }
 
 // NewDID creates a new *w3c.DID from the type and the genesis
-func NewDID(typ [2]byte, genesis [genesisLn]byte) (*w3c.DID, error) {
+func NewDID(typ DIDType, genesis [genesisLn]byte) (*w3c.DID, error) {
 	return ParseDIDFromID(NewID(typ, genesis))
 }
 
+// IDFromDID returns ID from DID. DIDs of unknown methods are mapped to IDs of
+// DIDMethodOther type by the hash of DID canonical form (see
+// CanonicalUnsupportedDID), so DID URLs of the same subject map to the same
+// ID. Use RecordUnsupportedDID instead to make the mapping reversible by
+// ParseDIDFromID.
 func IDFromDID(did w3c.DID) (ID, error) {
 	id, err := idFromDID(did)
 	if errors.Is(err, ErrMethodUnknown) {
-		return newIDFromUnsupportedDID(did), nil
+		return newIDFromUnsupportedDID(hashedUnsupportedDID(did)), nil
 	}
 	return id, err
 }
 
+// newIDFromUnsupportedDID returns ID of DID of unknown method by the hash of
+// DID string. DID must be already in the form returned by hashedUnsupportedDID.
 func newIDFromUnsupportedDID(did w3c.DID) ID {
 	hash := sha256.Sum256([]byte(did.String()))
 	var genesis [genesisLn]byte
 	copy(genesis[:], hash[len(hash)-genesisLn:])
 	flg := DIDNetworkFlag{Blockchain: UnknownChain, NetworkID: UnknownNetwork}
-	var tp = [2]byte{
+	var tp = DIDType{
 		DIDMethodByte[DIDMethodOther],
 		DIDMethodNetwork[DIDMethodOther][flg],
 	}
@@ -420,18 +433,21 @@ func idFromDID(did w3c.DID) (ID, error) {
 	var id ID
 
 	if len(did.IDStrings) > 3 || len(did.IDStrings) < 2 {
-		return id, fmt.Errorf("%w: unexpected number of ID strings",
-			ErrIncorrectDID)
+		return id, newDIDParseError(did, 0, ParseErrorIden3ID,
+			"unexpected number of ID strings")
 	}
 
+	idIdx := len(did.IDStrings) - 1
 	var err error
-	id, err = IDFromString(did.IDStrings[len(did.IDStrings)-1])
+	id, err = IDFromString(did.IDStrings[idIdx])
 	if err != nil {
-		return id, fmt.Errorf("%w: can't parse ID string", ErrIncorrectDID)
+		return id, newDIDParseError(did, idIdx,
+			idStringErrorCategory(did.IDStrings[idIdx]), "can't parse ID string")
 	}
 
 	if !CheckChecksum(id) {
-		return id, fmt.Errorf("%w: incorrect ID checksum", ErrIncorrectDID)
+		return id, newDIDParseError(did, idIdx, ParseErrorIden3Checksum,
+			"incorrect ID checksum")
 	}
 
 	method2, blockchain, networkID, err := decodeDIDPartsFromID(id)
@@ -440,24 +456,27 @@ func idFromDID(did w3c.DID) (ID, error) {
 	}
 
 	if method2 != method {
-		return id, fmt.Errorf("%w: methods in ID and DID are different",
-			ErrIncorrectDID)
+		return id, newDIDParseError(did, -1, ParseErrorNetworkMismatch,
+			"methods in ID and DID are different")
 	}
 
 	if string(blockchain) != did.IDStrings[0] {
-		return id, fmt.Errorf("%w: blockchains in ID and DID are different",
-			ErrIncorrectDID)
+		return id, newDIDParseError(did, 0, ParseErrorNetworkMismatch,
+			"blockchains in ID and DID are different")
 	}
 
 	if len(did.IDStrings) > 2 && string(networkID) != did.IDStrings[1] {
-		return id, fmt.Errorf("%w: networkIDs in ID and DID are different",
-			ErrIncorrectDID)
+		return id, newDIDParseError(did, 1, ParseErrorNetworkMismatch,
+			"networkIDs in ID and DID are different")
 	}
 
 	return id, nil
 }
 
-// ParseDIDFromID returns DID from ID
+// ParseDIDFromID returns DID from ID. IDs of DIDs of unknown methods are
+// looked up in UnsupportedDIDStore (see RecordUnsupportedDID) and the DID the
+// ID is calculated from is returned. ErrMethodUnknown is returned if there is
+// no record for such ID.
 func ParseDIDFromID(id ID) (*w3c.DID, error) {
 
 	if !CheckChecksum(id) {
@@ -470,8 +489,11 @@ func ParseDIDFromID(id ID) (*w3c.DID, error) {
 	}
 
 	if isUnsupportedDID(method, blockchain, networkID) {
-		return nil, fmt.Errorf("%w: unsupported DID",
-			ErrMethodUnknown)
+		rec, err := LookupUnsupportedDID(id)
+		if err != nil {
+			return nil, err
+		}
+		return &rec.DID, nil
 	}
 
 	didParts := []string{"did", string(method), string(blockchain)}
@@ -491,22 +513,7 @@ func ParseDIDFromID(id ID) (*w3c.DID, error) {
 }
 
 func decodeDIDPartsFromID(id ID) (DIDMethod, Blockchain, NetworkID, error) {
-	method, err := FindDIDMethodByValue(id[0])
-	if err != nil {
-		return DIDMethodOther, UnknownChain, UnknownNetwork, err
-	}
-
-	blockchain, err := FindBlockchainForDIDMethodByValue(method, id[1])
-	if err != nil {
-		return DIDMethodOther, UnknownChain, UnknownNetwork, err
-	}
-
-	networkID, err := FindNetworkIDForDIDMethodByValue(method, id[1])
-	if err != nil {
-		return DIDMethodOther, UnknownChain, UnknownNetwork, err
-	}
-
-	return method, blockchain, networkID, nil
+	return id.Type().decode()
 }
 
 func MethodFromID(id ID) (DIDMethod, error) {
@@ -552,14 +559,13 @@ func NetworkIDFromID(id ID) (NetworkID, error) {
 }
 
 func EthAddressFromID(id ID) ([20]byte, error) {
-	var z [7]byte
-	if !bytes.Equal(z[:], id[2:2+len(z)]) {
+	if !IsEthereumControlled(id) {
 		return [20]byte{}, errors.New(
 			"can't get Ethereum address: high bytes of genesis are not zero")
 	}
 
 	var address [20]byte
-	copy(address[:], id[2+7:])
+	copy(address[:], id[2+genesisLn-ethAddressLn:])
 	return address, nil
 }
 
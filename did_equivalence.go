@@ -0,0 +1,42 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// EquivalentDIDs returns true if DIDs identify the same subject. URL
+// components of DIDs are ignored. DIDs are equivalent if their base DIDs are
+// equal by w3c.Equal or if they map to the same ID, so a DID and the DID
+// returned by ParseDIDFromID for its ID are equivalent, e.g.
+// did:iden3:polygon:<id> and did:iden3:polygon:amoy:<id>. DIDs of unknown
+// methods are compared by their canonical forms (see CanonicalUnsupportedDID).
+//
+// Error is returned if DID of iden3 based method is malformed.
+func EquivalentDIDs(a, b w3c.DID) (bool, error) {
+	baseA, baseB := a.Base(), b.Base()
+	if w3c.Equal(baseA, baseB) {
+		return true, nil
+	}
+
+	idA, err := subjectID(baseA)
+	if err != nil {
+		return false, err
+	}
+	idB, err := subjectID(baseB)
+	if err != nil {
+		return false, err
+	}
+	return idA == idB, nil
+}
+
+// subjectID returns ID of DID, the error mentions the DID
+func subjectID(did w3c.DID) (ID, error) {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return ID{}, fmt.Errorf("can't get ID of DID %v: %w", did.String(),
+			err)
+	}
+	return id, nil
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEquivalentDIDs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		a, b       string
+		equivalent bool
+	}{
+		{
+			name:       "DID URL",
+			a:          "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr",
+			b:          "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr#state-info",
+			equivalent: true,
+		},
+		{
+			name:       "DID without network",
+			a:          "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr",
+			b:          "did:iden3:polygon:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr?service=x",
+			equivalent: true,
+		},
+		{
+			name:       "different identities",
+			a:          "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr",
+			b:          "did:polygonid:polygon:amoy:2qQ68JkRcf3xrHPQPWZei3YeVzHPP58wYNxx2mEouR",
+			equivalent: false,
+		},
+		{
+			name:       "unsupported DIDs with the same canonical form",
+			a:          "did:web:Example.COM:user",
+			b:          "did:web:example.com:user#key-1",
+			equivalent: true,
+		},
+		{
+			name:       "different unsupported DIDs",
+			a:          "did:web:example.com:user",
+			b:          "did:web:example.com:user2",
+			equivalent: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := w3c.ParseDID(tc.a)
+			require.NoError(t, err)
+			b, err := w3c.ParseDID(tc.b)
+			require.NoError(t, err)
+			eq, err := EquivalentDIDs(*a, *b)
+			require.NoError(t, err)
+			require.Equal(t, tc.equivalent, eq)
+			eq, err = EquivalentDIDs(*b, *a)
+			require.NoError(t, err)
+			require.Equal(t, tc.equivalent, eq)
+		})
+	}
+}
+
+func TestEquivalentDIDs_IDDerivedDID(t *testing.T) {
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+	id, err := IDFromDID(*did)
+	require.NoError(t, err)
+	did2, err := ParseDIDFromID(id)
+	require.NoError(t, err)
+	require.NotEqual(t, did.String(), did2.String())
+
+	eq, err := EquivalentDIDs(*did, *did2)
+	require.NoError(t, err)
+	require.True(t, eq)
+}
+
+func TestEquivalentDIDs_Invalid(t *testing.T) {
+	a, err := w3c.ParseDID(
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+	b, err := w3c.ParseDID(
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDs")
+	require.NoError(t, err)
+
+	_, err = EquivalentDIDs(*a, *b)
+	require.ErrorIs(t, err, ErrIncorrectDID)
+	require.EqualError(t, err, "can't get ID of DID "+b.String()+
+		": incorrect DID: can't parse ID string")
+}
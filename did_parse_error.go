@@ -0,0 +1,62 @@
+package core
+
+import (
+	"bytes"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/mr-tron/base58"
+)
+
+// Categories of w3c.ParseError returned for incorrect DIDs of iden3 based
+// methods
+const (
+	// ParseErrorIden3ID is an error of ID strings of DID, e.g. ID is not
+	// base58 encoded ID
+	ParseErrorIden3ID w3c.ParseErrorCategory = "iden3 id"
+	// ParseErrorIden3Checksum is an error of ID checksum
+	ParseErrorIden3Checksum w3c.ParseErrorCategory = "iden3 checksum"
+	// ParseErrorNetworkMismatch is an error of method, blockchain or network
+	// of DID that are different from the ones encoded in ID type
+	ParseErrorNetworkMismatch w3c.ParseErrorCategory = "network mismatch"
+)
+
+// grammar rules of DIDs of iden3 based methods by error categories
+var iden3ParseErrorRules = map[w3c.ParseErrorCategory]string{
+	ParseErrorIden3ID:         `did = "did:" method ":" blockchain [ ":" network ] ":" id`,
+	ParseErrorIden3Checksum:   `id = base58( type genesis checksum )`,
+	ParseErrorNetworkMismatch: `type = method-byte network-flag`,
+}
+
+// newDIDParseError returns *w3c.ParseError that wraps ErrIncorrectDID at
+// the ID string of DID with the index idStringIdx or at the method if
+// idStringIdx is negative
+func newDIDParseError(did w3c.DID, idStringIdx int,
+	category w3c.ParseErrorCategory, msg string) error {
+
+	offset := len("did:")
+	if idStringIdx >= 0 {
+		offset += len(did.Method) + 1
+		for i := 0; i < idStringIdx && i < len(did.IDStrings); i++ {
+			offset += len(did.IDStrings[i]) + 1
+		}
+	}
+	return &w3c.ParseError{
+		Input:    did.String(),
+		Offset:   offset,
+		Rule:     iden3ParseErrorRules[category],
+		Category: category,
+		Msg:      msg,
+		Err:      ErrIncorrectDID,
+	}
+}
+
+// idStringErrorCategory returns the category of ID string that can't be
+// parsed as ID. Well-formed IDs with incorrect checksum are rejected by
+// IDFromString, so they are reported as checksum errors.
+func idStringErrorCategory(idString string) w3c.ParseErrorCategory {
+	b, err := base58.Decode(idString)
+	if err != nil || len(b) != len(ID{}) || bytes.Equal(b, emptyID[:]) {
+		return ParseErrorIden3ID
+	}
+	return ParseErrorIden3Checksum
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDFromDID_ParseError(t *testing.T) {
+	const idStr = "xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr"
+
+	testCases := []struct {
+		name     string
+		did      string
+		offset   int
+		category w3c.ParseErrorCategory
+		msg      string
+	}{
+		{
+			name:     "unexpected number of ID strings",
+			did:      "did:iden3:polygon:amoy:main:" + idStr,
+			offset:   10,
+			category: ParseErrorIden3ID,
+			msg:      "incorrect DID: unexpected number of ID strings",
+		},
+		{
+			name:     "invalid ID",
+			did:      "did:iden3:polygon:amoy:123",
+			offset:   23,
+			category: ParseErrorIden3ID,
+			msg:      "incorrect DID: can't parse ID string",
+		},
+		{
+			name:     "invalid checksum",
+			did:      "did:iden3:polygon:amoy:" + idStr[:len(idStr)-1] + "s",
+			offset:   23,
+			category: ParseErrorIden3Checksum,
+			msg:      "incorrect DID: can't parse ID string",
+		},
+		{
+			name:     "method mismatch",
+			did:      "did:polygonid:polygon:amoy:" + idStr,
+			offset:   4,
+			category: ParseErrorNetworkMismatch,
+			msg:      "incorrect DID: methods in ID and DID are different",
+		},
+		{
+			name:     "blockchain mismatch",
+			did:      "did:iden3:eth:amoy:" + idStr,
+			offset:   10,
+			category: ParseErrorNetworkMismatch,
+			msg:      "incorrect DID: blockchains in ID and DID are different",
+		},
+		{
+			name:     "network mismatch",
+			did:      "did:iden3:polygon:mumbai:" + idStr,
+			offset:   18,
+			category: ParseErrorNetworkMismatch,
+			msg:      "incorrect DID: networkIDs in ID and DID are different",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			did, err := w3c.ParseDID(tc.did)
+			require.NoError(t, err)
+
+			_, err = idFromDID(*did)
+			require.EqualError(t, err, tc.msg)
+			require.ErrorIs(t, err, ErrIncorrectDID)
+
+			var parseErr *w3c.ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.Equal(t, tc.did, parseErr.Input)
+			require.Equal(t, tc.offset, parseErr.Offset)
+			require.Equal(t, tc.category, parseErr.Category)
+			require.NotEmpty(t, parseErr.Rule)
+		})
+	}
+}
@@ -51,7 +51,7 @@ func TestParseDID(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, NoNetwork, networkID)
 
-	require.Equal(t, [2]byte{DIDMethodByte[DIDMethodIden3], 0b0}, id.Type())
+	require.Equal(t, DIDType{DIDMethodByte[DIDMethodIden3], 0b0}, id.Type())
 }
 
 func TestDID_MarshalJSON(t *testing.T) {
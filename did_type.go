@@ -0,0 +1,154 @@
+package core
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DIDType is a 2-byte type of ID
+// - first byte: DID method
+// - second byte: blockchain and network
+type DIDType [2]byte
+
+// Method returns DID method of the type or DIDMethodOther if method byte is
+// not registered.
+func (t DIDType) Method() DIDMethod {
+	method, _, _, err := t.decode()
+	if err != nil {
+		return DIDMethodOther
+	}
+	return method
+}
+
+// Blockchain returns blockchain of the type or UnknownChain if type is not
+// registered.
+func (t DIDType) Blockchain() Blockchain {
+	_, blockchain, _, err := t.decode()
+	if err != nil {
+		return UnknownChain
+	}
+	return blockchain
+}
+
+// Network returns network of the type or UnknownNetwork if type is not
+// registered.
+func (t DIDType) Network() NetworkID {
+	_, _, networkID, err := t.decode()
+	if err != nil {
+		return UnknownNetwork
+	}
+	return networkID
+}
+
+// IsReadOnly returns true if the type is a registered readonly type, i.e.
+// identity is not bound to any blockchain.
+func (t DIDType) IsReadOnly() bool {
+	_, blockchain, networkID, err := t.decode()
+	return err == nil && blockchain == ReadOnly && networkID == NoNetwork
+}
+
+// IsUnsupported returns true if the type is the one used for IDs created from
+// DIDs of unknown methods.
+func (t DIDType) IsUnsupported() bool {
+	method, blockchain, networkID, err := t.decode()
+	return err == nil && isUnsupportedDID(method, blockchain, networkID)
+}
+
+// String returns the type in the same form it takes in DID, e.g.
+// iden3:polygon:amoy or iden3:readonly. Types with version 2 flag have /v2
+// suffix. Unregistered and unsupported types are returned as 0x prefixed hex
+// of two bytes.
+func (t DIDType) String() string {
+	method, blockchain, networkID, err := t.decode()
+	if err != nil || isUnsupportedDID(method, blockchain, networkID) {
+		return "0x" + hex.EncodeToString(t[:])
+	}
+
+	parts := []string{string(method), string(blockchain)}
+	if networkID != NoNetwork {
+		parts = append(parts, string(networkID))
+	}
+	str := strings.Join(parts, ":")
+	if _, ok := findDIDMethodByExactValue(t[0]); !ok && t.hasIDVersion2Flag() {
+		str += "/" + IDVersion2.String()
+	}
+	return str
+}
+
+// MarshalText returns string representation of the type
+func (t DIDType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses the type from its string representation
+func (t *DIDType) UnmarshalText(b []byte) error {
+	t2, err := ParseDIDType(string(b))
+	if err != nil {
+		return err
+	}
+	*t = t2
+	return nil
+}
+
+// ParseDIDType parses the type from method:blockchain[:network][/version]
+// string or from 0x prefixed hex of two bytes.
+func ParseDIDType(s string) (DIDType, error) {
+	if strings.HasPrefix(s, "0x") {
+		var t DIDType
+		b, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return DIDType{}, fmt.Errorf("invalid DID type hex: %w", err)
+		}
+		if len(b) != len(t) {
+			return DIDType{}, fmt.Errorf("invalid DID type length: %d", len(b))
+		}
+		copy(t[:], b)
+		return t, nil
+	}
+
+	s, version, hasVersion := strings.Cut(s, "/")
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return DIDType{}, fmt.Errorf("invalid DID type: %s", s)
+	}
+
+	network := NoNetwork
+	if len(parts) == 3 {
+		network = NetworkID(parts[2])
+	}
+
+	t, err := BuildDIDType(DIDMethod(parts[0]), Blockchain(parts[1]), network)
+	if err != nil || !hasVersion {
+		return t, err
+	}
+
+	switch version {
+	case IDVersion1.String():
+		return t, nil
+	case IDVersion2.String():
+		return t.WithIDVersion(IDVersion2)
+	default:
+		return DIDType{}, fmt.Errorf("%w: %s", ErrIDVersionNotSupported,
+			version)
+	}
+}
+
+func (t DIDType) decode() (DIDMethod, Blockchain, NetworkID, error) {
+	method, err := FindDIDMethodByValue(t[0])
+	if err != nil {
+		return DIDMethodOther, UnknownChain, UnknownNetwork, err
+	}
+
+	blockchain, err := FindBlockchainForDIDMethodByValue(method, t[1])
+	if err != nil {
+		return DIDMethodOther, UnknownChain, UnknownNetwork, err
+	}
+
+	networkID, err := FindNetworkIDForDIDMethodByValue(method, t[1])
+	if err != nil {
+		return DIDMethodOther, UnknownChain, UnknownNetwork, err
+	}
+
+	return method, blockchain, networkID, nil
+}
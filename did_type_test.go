@@ -0,0 +1,78 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDIDType(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, Polygon, Amoy)
+	require.NoError(t, err)
+	require.Equal(t, DIDType{0b00000001, 0b00010011}, typ)
+	require.Equal(t, DIDMethodIden3, typ.Method())
+	require.Equal(t, Polygon, typ.Blockchain())
+	require.Equal(t, Amoy, typ.Network())
+	require.False(t, typ.IsReadOnly())
+	require.False(t, typ.IsUnsupported())
+	require.Equal(t, "iden3:polygon:amoy", typ.String())
+
+	typ, err = BuildDIDType(DIDMethodPolygonID, ReadOnly, NoNetwork)
+	require.NoError(t, err)
+	require.True(t, typ.IsReadOnly())
+	require.Equal(t, "polygonid:readonly", typ.String())
+
+	typ = DIDType{0xff, 0xff}
+	require.True(t, typ.IsUnsupported())
+	require.Equal(t, DIDMethodOther, typ.Method())
+	require.Equal(t, "0xffff", typ.String())
+
+	typ = DIDType{0x01, 0xfe}
+	require.Equal(t, DIDMethodOther, typ.Method())
+	require.Equal(t, UnknownChain, typ.Blockchain())
+	require.Equal(t, UnknownNetwork, typ.Network())
+	require.False(t, typ.IsReadOnly())
+	require.False(t, typ.IsUnsupported())
+	require.Equal(t, "0x01fe", typ.String())
+}
+
+func TestParseDIDType(t *testing.T) {
+	for _, in := range []string{"iden3:polygon:amoy", "polygonid:readonly",
+		"iden3:eth:sepolia", "0xffff", "0x01fe"} {
+		typ, err := ParseDIDType(in)
+		require.NoError(t, err, in)
+		require.Equal(t, in, typ.String())
+	}
+
+	for _, in := range []string{"", "iden3", "iden3:polygon:amoy:x",
+		"iden3:polygon:unknown_net", "unknown:polygon:amoy", "0x01", "0xzzzz"} {
+		_, err := ParseDIDType(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestDIDType_JSON(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, Ethereum, Main)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(typ)
+	require.NoError(t, err)
+	require.Equal(t, `"iden3:eth:main"`, string(b))
+
+	var typ2 DIDType
+	require.NoError(t, json.Unmarshal(b, &typ2))
+	require.Equal(t, typ, typ2)
+
+	require.Error(t, json.Unmarshal([]byte(`"iden3:eth"`), &typ2))
+}
+
+func TestID_DIDType(t *testing.T) {
+	id, err := IDFromString("wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+	require.Equal(t, "iden3:polygon:mumbai", id.Type().String())
+
+	typ, _, _, err := DecomposeID(id)
+	require.NoError(t, err)
+	require.Equal(t, Mumbai, typ.Network())
+}
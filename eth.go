@@ -0,0 +1,107 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/iden3/go-iden3-crypto/keccak256"
+)
+
+const ethAddressLn = 20
+
+var (
+	// ErrInvalidEthAddress returned when string is not a hex encoded
+	// Ethereum address.
+	ErrInvalidEthAddress = errors.New("invalid Ethereum address")
+	// ErrInvalidEthAddressChecksum returned when mixed-case Ethereum address
+	// does not match its EIP-55 checksum.
+	ErrInvalidEthAddressChecksum = errors.New(
+		"invalid Ethereum address checksum")
+)
+
+// NewIDFromEthAddress creates Ethereum-controlled ID of given method,
+// blockchain and network. The genesis of such ID is the address padded with
+// zero bytes.
+func NewIDFromEthAddress(method DIDMethod, blockchain Blockchain,
+	network NetworkID, address [ethAddressLn]byte) (ID, error) {
+
+	typ, err := BuildDIDType(method, blockchain, network)
+	if err != nil {
+		return ID{}, err
+	}
+	return NewID(typ, GenesisFromEthAddress(address)), nil
+}
+
+// NewDIDFromEthAddress creates Ethereum-controlled DID of given method,
+// blockchain and network.
+func NewDIDFromEthAddress(method DIDMethod, blockchain Blockchain,
+	network NetworkID, address [ethAddressLn]byte) (*w3c.DID, error) {
+
+	id, err := NewIDFromEthAddress(method, blockchain, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDIDFromID(id)
+}
+
+// IsEthereumControlled returns true if ID genesis is an Ethereum address,
+// i.e. high 7 bytes of genesis are zero.
+func IsEthereumControlled(id ID) bool {
+	for _, b := range id[2 : 2+genesisLn-ethAddressLn] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EthAddressFromHex parses 0x prefixed hex encoded Ethereum address. If the
+// address is in mixed case, it is validated against EIP-55 checksum.
+// All-lowercase and all-uppercase addresses are accepted as is.
+func EthAddressFromHex(s string) ([ethAddressLn]byte, error) {
+	var addr [ethAddressLn]byte
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return addr, fmt.Errorf("%w: missing 0x prefix", ErrInvalidEthAddress)
+	}
+	h := s[2:]
+	if len(h) != hex.EncodedLen(ethAddressLn) {
+		return addr, fmt.Errorf("%w: invalid length", ErrInvalidEthAddress)
+	}
+	_, err := hex.Decode(addr[:], []byte(h))
+	if err != nil {
+		return [ethAddressLn]byte{}, fmt.Errorf("%w: %v", ErrInvalidEthAddress,
+			err)
+	}
+
+	if h != strings.ToLower(h) && h != strings.ToUpper(h) &&
+		h != EthAddressToChecksumHex(addr)[2:] {
+
+		return [ethAddressLn]byte{}, ErrInvalidEthAddressChecksum
+	}
+
+	return addr, nil
+}
+
+// EthAddressToChecksumHex returns 0x prefixed hex representation of Ethereum
+// address with EIP-55 mixed-case checksum.
+func EthAddressToChecksumHex(addr [ethAddressLn]byte) string {
+	h := []byte(hex.EncodeToString(addr[:]))
+	hash := keccak256.Hash(h)
+	for i, c := range h {
+		if c < 'a' {
+			continue
+		}
+		// the nibble of hash at the same position as the hex char
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			h[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(h)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthAddressFromHex(t *testing.T) {
+	want := ethAddrFromHex("a51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0")
+
+	for _, in := range []string{
+		"0xA51c1fc2f0D1a1b8494Ed1FE312d7C3a78Ed91C0",
+		"0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0",
+		"0XA51C1FC2F0D1A1B8494ED1FE312D7C3A78ED91C0",
+	} {
+		addr, err := EthAddressFromHex(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, addr)
+	}
+
+	_, err := EthAddressFromHex("0xa51c1fc2f0D1a1b8494Ed1FE312d7C3a78Ed91C0")
+	require.ErrorIs(t, err, ErrInvalidEthAddressChecksum)
+
+	for _, in := range []string{
+		"a51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0",
+		"0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91",
+		"0xz51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0",
+	} {
+		_, err = EthAddressFromHex(in)
+		require.ErrorIs(t, err, ErrInvalidEthAddress, in)
+	}
+}
+
+func TestEthAddressToChecksumHex(t *testing.T) {
+	// test vectors from EIP-55
+	for _, want := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		addr, err := EthAddressFromHex(want)
+		require.NoError(t, err)
+		require.Equal(t, want, EthAddressToChecksumHex(addr))
+	}
+}
+
+func TestNewIDFromEthAddress(t *testing.T) {
+	addr, err := EthAddressFromHex("0xA51c1fc2f0D1a1b8494Ed1FE312d7C3a78Ed91C0")
+	require.NoError(t, err)
+
+	id, err := NewIDFromEthAddress(DIDMethodPolygonID, Polygon, Mumbai, addr)
+	require.NoError(t, err)
+	require.Equal(t, "2qCU58EJgrEM9NKvHkvg5NFWUiJPgN3M3LnCr98j3x", id.String())
+	require.True(t, IsEthereumControlled(id))
+
+	did, err := NewDIDFromEthAddress(DIDMethodPolygonID, Polygon, Mumbai, addr)
+	require.NoError(t, err)
+	require.Equal(t,
+		"did:polygonid:polygon:mumbai:2qCU58EJgrEM9NKvHkvg5NFWUiJPgN3M3LnCr98j3x",
+		did.String())
+
+	_, err = NewIDFromEthAddress(DIDMethodPolygonID, Polygon, "unknown_net",
+		addr)
+	require.ErrorIs(t, err, ErrNetworkNotSupportedForDID)
+}
+
+func TestIsEthereumControlled(t *testing.T) {
+	id, err := IDFromString("wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+	require.False(t, IsEthereumControlled(id))
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// GenesisVerification is a detailed result of the ID genesis verification
+type GenesisVerification struct {
+	// State is the Identity State calculated from the tree roots
+	State State
+	// ExpectedID is the ID calculated from State with the type and the
+	// version of verified ID
+	ExpectedID ID
+	// ExpectedGenesis is the genesis calculated from State
+	ExpectedGenesis [genesisLn]byte
+	// GenesisMatch is true if the genesis of verified ID equals
+	// ExpectedGenesis
+	GenesisMatch bool
+	// TypeRegistered is true if the type of verified ID is a registered DID type
+	TypeRegistered bool
+}
+
+// Valid returns true if genesis of verified ID matches and its type is
+// registered
+func (v *GenesisVerification) Valid() bool {
+	return v.GenesisMatch && v.TypeRegistered
+}
+
+// VerifyGenesis checks that the ID is a genesis ID of the Identity State
+// calculated from the Claims Tree Root, Revocation Tree Root and Roots Tree
+// Root. Error is returned only if ID checksum is invalid or state can't be
+// calculated, mismatches are reported in the result.
+func VerifyGenesis(id ID, claimsRoot, revRoot,
+	rootsRoot *big.Int) (*GenesisVerification, error) {
+
+	if !CheckChecksum(id) {
+		return nil, fmt.Errorf("%w: invalid checksum", ErrUnsupportedID)
+	}
+
+	state, err := StateFromTreeRoots(claimsRoot, revRoot, rootsRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedID, err := genesisIDOf(id, state.BigInt())
+	if err != nil {
+		return nil, err
+	}
+
+	_, expectedGenesis, _, err := DecomposeID(expectedID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, genesis, _, err := DecomposeID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	method, blockchain, networkID, err := decodeDIDPartsFromID(id)
+	typeRegistered := err == nil && !isUnsupportedDID(method, blockchain, networkID)
+
+	return &GenesisVerification{
+		State:           state,
+		ExpectedID:      expectedID,
+		ExpectedGenesis: expectedGenesis,
+		GenesisMatch:    genesis == expectedGenesis,
+		TypeRegistered:  typeRegistered,
+	}, nil
+}
+
+// VerifyGenesisDID checks that the DID is a genesis DID of the Identity State
+// calculated from the Claims Tree Root, Revocation Tree Root and Roots Tree
+// Root.
+func VerifyGenesisDID(did w3c.DID, claimsRoot, revRoot,
+	rootsRoot *big.Int) (*GenesisVerification, error) {
+
+	id, err := IDFromDID(did)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyGenesis(id, claimsRoot, revRoot, rootsRoot)
+}
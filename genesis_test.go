@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyGenesis(t *testing.T) {
+	clr, rer, ror := big.NewInt(1), big.NewInt(2), big.NewInt(3)
+
+	id, err := IDFromString("xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+
+	res, err := VerifyGenesis(id, clr, rer, ror)
+	require.NoError(t, err)
+	require.True(t, res.Valid())
+	require.True(t, res.GenesisMatch)
+	require.True(t, res.TypeRegistered)
+	require.Equal(t, id, res.ExpectedID)
+	require.Equal(t,
+		"6542985608222806190361240322586112750744169038454362455181422643027100751666",
+		res.State.String())
+
+	ok, err := CheckGenesisStateID(id.BigInt(), res.State.BigInt())
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// wrong roots
+	res, err = VerifyGenesis(id, clr, rer, big.NewInt(4))
+	require.NoError(t, err)
+	require.False(t, res.Valid())
+	require.False(t, res.GenesisMatch)
+	require.True(t, res.TypeRegistered)
+	require.NotEqual(t, id, res.ExpectedID)
+
+	// unregistered type with the right genesis
+	_, genesis, _, err := DecomposeID(id)
+	require.NoError(t, err)
+	unknownTypeID := NewID(DIDType{0x01, 0xfe}, genesis)
+	res, err = VerifyGenesis(unknownTypeID, clr, rer, ror)
+	require.NoError(t, err)
+	require.False(t, res.Valid())
+	require.True(t, res.GenesisMatch)
+	require.False(t, res.TypeRegistered)
+
+	// invalid checksum
+	id[30]++
+	_, err = VerifyGenesis(id, clr, rer, ror)
+	require.ErrorIs(t, err, ErrUnsupportedID)
+}
+
+func TestVerifyGenesis_IDVersion2(t *testing.T) {
+	clr, rer, ror := big.NewInt(1), big.NewInt(2), big.NewInt(3)
+
+	idV1, err := IDFromString("xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+	_, genesis, _, err := DecomposeID(idV1)
+	require.NoError(t, err)
+	id, err := NewIDWithVersion(idV1.Type(), genesis, IDVersion2)
+	require.NoError(t, err)
+
+	res, err := VerifyGenesis(id, clr, rer, ror)
+	require.NoError(t, err)
+	require.True(t, res.Valid())
+	require.Equal(t, id, res.ExpectedID)
+
+	ok, err := CheckGenesisStateID(id.BigInt(), res.State.BigInt())
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestVerifyGenesisDID(t *testing.T) {
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+
+	res, err := VerifyGenesisDID(*did, big.NewInt(1), big.NewInt(2),
+		big.NewInt(3))
+	require.NoError(t, err)
+	require.True(t, res.Valid())
+
+	did, err = w3c.ParseDID(
+		"did:iden3:polygon:main:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+	_, err = VerifyGenesisDID(*did, big.NewInt(1), big.NewInt(2),
+		big.NewInt(3))
+	require.ErrorIs(t, err, ErrIncorrectDID)
+}
@@ -13,7 +13,7 @@ import (
 var (
 	// TypeDefault specifies the regular identity
 	// - first 2 bytes: `00000000 00000000`
-	TypeDefault = [2]byte{0x00, 0x00}
+	TypeDefault = DIDType{0x00, 0x00}
 
 	// TypeDID specifies the identity with iden3 method in specific networks
 	// - first byte: did method e.g. 00000001 - iden3 did method
@@ -34,8 +34,11 @@ const genesisLn = 27
 type ID [idLength]byte
 
 // NewID creates a new ID from a type and genesis
-func NewID(typ [2]byte, genesis [genesisLn]byte) ID {
-	checksum := CalculateChecksum(typ, genesis)
+func NewID(typ DIDType, genesis [genesisLn]byte) ID {
+	return newID(typ, genesis, CalculateChecksum(typ, genesis))
+}
+
+func newID(typ DIDType, genesis [genesisLn]byte, checksum [2]byte) ID {
 	var b ID
 	copy(b[:2], typ[:])
 	copy(b[2:], genesis[:])
@@ -60,9 +63,15 @@ func ProfileID(id ID, nonce *big.Int) (ID, error) {
 		return ID{}, err
 	}
 
+	// profiles keep the version of the identity
+	version, ok := idVersion(id)
+	if !ok {
+		version = IDVersion1
+	}
+
 	var genesis [genesisLn]byte
 	copy(genesis[:], firstNBytes(hash, genesisLn))
-	return NewID(typ, genesis), nil
+	return NewIDWithVersion(typ, genesis, version)
 }
 
 // firstNBytes encodes big int in little endian representation and return
@@ -113,8 +122,9 @@ func (id *ID) Equals(id2 *ID) bool {
 	return bytes.Equal(id[:], id2[:])
 }
 
-func (id *ID) Type() [2]byte {
-	var typ [2]byte
+// Type returns the type of the ID
+func (id *ID) Type() DIDType {
+	var typ DIDType
 	copy(typ[:], id[:2])
 	return typ
 }
@@ -160,7 +170,7 @@ func IDFromInt(i *big.Int) (ID, error) {
 }
 
 // DecomposeID returns type, genesis and checksum from an ID
-func DecomposeID(id ID) (typ [2]byte, genesis [genesisLn]byte, checksum [2]byte,
+func DecomposeID(id ID) (typ DIDType, genesis [genesisLn]byte, checksum [2]byte,
 	err error) {
 
 	copy(typ[:], id[:2])
@@ -169,11 +179,11 @@ func DecomposeID(id ID) (typ [2]byte, genesis [genesisLn]byte, checksum [2]byte,
 	return typ, genesis, checksum, nil
 }
 
-// CalculateChecksum returns the checksum for a given type and genesis_root,
-// where checksum:
+// CalculateChecksum returns the checksum of version 1 IDs for a given type
+// and genesis_root, where checksum:
 //
 //	hash( [type | root_genesis ] )
-func CalculateChecksum(typ [2]byte, genesis [genesisLn]byte) [2]byte {
+func CalculateChecksum(typ DIDType, genesis [genesisLn]byte) [2]byte {
 	var toChecksum [29]byte
 	copy(toChecksum[:], typ[:])
 	copy(toChecksum[2:], genesis[:])
@@ -187,21 +197,16 @@ func CalculateChecksum(typ [2]byte, genesis [genesisLn]byte) [2]byte {
 	return checksum
 }
 
-// CheckChecksum returns a bool indicating if the ID.Checksum is consistent with the rest of the ID data
+// CheckChecksum returns a bool indicating if the ID.Checksum is consistent with the rest of the ID data.
+// Version 1 checksum is valid for any type, version 2 checksum is valid for
+// types with version 2 flag (see IDVersionFromID).
 func CheckChecksum(id ID) bool {
-	typ, genesis, checksum, err := DecomposeID(id)
-	if err != nil {
-		return false
-	}
-	if bytes.Equal(checksum[:], []byte{0, 0}) {
-		return false
-	}
-	c := CalculateChecksum(typ, genesis)
-	return bytes.Equal(c[:], checksum[:])
+	_, ok := idVersion(id)
+	return ok
 }
 
 // NewIDFromIdenState calculates the genesis ID from an Identity State.
-func NewIDFromIdenState(typ [2]byte, state *big.Int) (*ID, error) {
+func NewIDFromIdenState(typ DIDType, state *big.Int) (*ID, error) {
 	var idGenesisBytes [genesisLn]byte
 
 	idenStateData, err := NewElemBytesFromInt(state)
@@ -227,10 +232,28 @@ func CheckGenesisStateID(id, state *big.Int) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	identifier, err := NewIDFromIdenState(userID.Type(), state)
+	identifier, err := genesisIDOf(userID, state)
 	if err != nil {
 		return false, err
 	}
 
 	return id.Cmp(identifier.BigInt()) == 0, nil
 }
+
+// genesisIDOf calculates the genesis ID from an Identity State with the type
+// and the version of the id
+func genesisIDOf(id ID, state *big.Int) (ID, error) {
+	genesisID, err := NewIDFromIdenState(id.Type(), state)
+	if err != nil {
+		return ID{}, err
+	}
+	version, ok := idVersion(id)
+	if !ok || version == IDVersion1 {
+		return *genesisID, nil
+	}
+	_, genesis, _, err := DecomposeID(*genesisID)
+	if err != nil {
+		return ID{}, err
+	}
+	return NewIDWithVersion(id.Type(), genesis, version)
+}
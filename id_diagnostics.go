@@ -0,0 +1,145 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mr-tron/base58"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// ErrInvalidIDString is wrapped by IDParseError
+var ErrInvalidIDString = errors.New("invalid ID string")
+
+// IDParseErrorKind is a kind of failure of ID string parsing
+type IDParseErrorKind string
+
+const (
+	// IDParseErrorInvalidChar means the string has character not from base58
+	// alphabet
+	IDParseErrorInvalidChar IDParseErrorKind = "invalid base58 character"
+	// IDParseErrorWrongLength means the decoded string is not 31 bytes long
+	IDParseErrorWrongLength IDParseErrorKind = "wrong length"
+	// IDParseErrorEmpty means all bytes of decoded ID are zero
+	IDParseErrorEmpty IDParseErrorKind = "empty ID"
+	// IDParseErrorZeroChecksum means the checksum bytes of ID are zero
+	IDParseErrorZeroChecksum IDParseErrorKind = "zero checksum"
+	// IDParseErrorChecksumMismatch means the checksum bytes of ID do not
+	// match type and genesis
+	IDParseErrorChecksumMismatch IDParseErrorKind = "checksum mismatch"
+)
+
+// IDParseError is a detailed error of ID string parsing returned by
+// ParseIDWithDiagnostics
+type IDParseError struct {
+	// Input is the string being parsed
+	Input string
+	// Kind is a kind of failure
+	Kind IDParseErrorKind
+	// Position is the 0-based character position of invalid character in
+	// Input. It is -1 for other kinds of failures.
+	Position int
+	// Char is the invalid character
+	Char rune
+	// Length is the length of decoded bytes for IDParseErrorWrongLength
+	Length int
+	// Suggestions are valid IDs of registered DID types that differ from the
+	// Input by single character. Only filled for IDParseErrorChecksumMismatch.
+	Suggestions []string
+}
+
+func (e *IDParseError) Error() string {
+	var msg string
+	switch e.Kind {
+	case IDParseErrorInvalidChar:
+		msg = fmt.Sprintf("%v %q at position %v", e.Kind, e.Char, e.Position)
+	case IDParseErrorWrongLength:
+		msg = fmt.Sprintf("%v: decoded to %v bytes, expected %v", e.Kind,
+			e.Length, idLength)
+	default:
+		msg = string(e.Kind)
+	}
+	if len(e.Suggestions) != 0 {
+		msg += fmt.Sprintf("; did you mean %v?",
+			strings.Join(e.Suggestions, " or "))
+	}
+	return fmt.Sprintf("%v %q: %v", ErrInvalidIDString, e.Input, msg)
+}
+
+func (e *IDParseError) Unwrap() error {
+	return ErrInvalidIDString
+}
+
+// ParseIDWithDiagnostics parses base58 ID string like IDFromString, but on
+// failure returns *IDParseError that pinpoints the kind of failure. For
+// checksum mismatch it proposes corrections of single character
+// substitutions that produce valid IDs of registered DID types.
+func ParseIDWithDiagnostics(s string) (ID, error) {
+	pos := 0
+	for _, c := range s {
+		if c >= utf8.RuneSelf || strings.IndexByte(base58Alphabet, byte(c)) == -1 {
+			return ID{}, &IDParseError{Input: s, Kind: IDParseErrorInvalidChar,
+				Position: pos, Char: c}
+		}
+		pos++
+	}
+
+	b, err := base58.Decode(s)
+	if err != nil || len(b) != idLength {
+		return ID{}, &IDParseError{Input: s, Kind: IDParseErrorWrongLength,
+			Position: -1, Length: len(b)}
+	}
+
+	if bytes.Equal(b, emptyID[:]) {
+		return ID{}, &IDParseError{Input: s, Kind: IDParseErrorEmpty,
+			Position: -1}
+	}
+
+	var id ID
+	copy(id[:], b)
+	if bytes.Equal(id[idLength-2:], []byte{0, 0}) {
+		return ID{}, &IDParseError{Input: s, Kind: IDParseErrorZeroChecksum,
+			Position: -1}
+	}
+
+	if !CheckChecksum(id) {
+		return ID{}, &IDParseError{Input: s, Kind: IDParseErrorChecksumMismatch,
+			Position: -1, Suggestions: suggestIDCorrections(s)}
+	}
+
+	return id, nil
+}
+
+// suggestIDCorrections returns IDs of registered DID types that differ from
+// s by single character substitution. s must be a valid base58 string.
+func suggestIDCorrections(s string) []string {
+	var suggestions []string
+	candidate := []byte(s)
+	for i := range candidate {
+		orig := candidate[i]
+		for j := 0; j < len(base58Alphabet); j++ {
+			if base58Alphabet[j] == orig {
+				continue
+			}
+			candidate[i] = base58Alphabet[j]
+			if isRegisteredIDString(string(candidate)) {
+				suggestions = append(suggestions, string(candidate))
+			}
+		}
+		candidate[i] = orig
+	}
+	return suggestions
+}
+
+func isRegisteredIDString(s string) bool {
+	id, err := IDFromString(s)
+	if err != nil {
+		return false
+	}
+	method, blockchain, networkID, err := decodeDIDPartsFromID(id)
+	return err == nil && !isUnsupportedDID(method, blockchain, networkID)
+}
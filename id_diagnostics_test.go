@@ -0,0 +1,73 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDWithDiagnostics(t *testing.T) {
+	want := "wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ"
+	id, err := ParseIDWithDiagnostics(want)
+	require.NoError(t, err)
+	require.Equal(t, want, id.String())
+
+	t.Run("invalid base58 char", func(t *testing.T) {
+		_, err := ParseIDWithDiagnostics("wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu0yMZ")
+		var parseErr *IDParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, IDParseErrorInvalidChar, parseErr.Kind)
+		require.Equal(t, 37, parseErr.Position)
+		require.Equal(t, '0', parseErr.Char)
+		require.ErrorIs(t, err, ErrInvalidIDString)
+		require.EqualError(t, err, `invalid ID string `+
+			`"wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu0yMZ": `+
+			`invalid base58 character '0' at position 37`)
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		_, err := ParseIDWithDiagnostics("wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yM")
+		var parseErr *IDParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, IDParseErrorWrongLength, parseErr.Kind)
+		require.Equal(t, 30, parseErr.Length)
+		require.Equal(t, -1, parseErr.Position)
+
+		_, err = ParseIDWithDiagnostics("")
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, IDParseErrorWrongLength, parseErr.Kind)
+	})
+
+	t.Run("empty ID", func(t *testing.T) {
+		_, err := ParseIDWithDiagnostics(base58.Encode(emptyID[:]))
+		var parseErr *IDParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, IDParseErrorEmpty, parseErr.Kind)
+	})
+
+	t.Run("zero checksum", func(t *testing.T) {
+		zeroChecksum := id
+		zeroChecksum[29], zeroChecksum[30] = 0, 0
+		_, err := ParseIDWithDiagnostics(zeroChecksum.String())
+		var parseErr *IDParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, IDParseErrorZeroChecksum, parseErr.Kind)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		typo := "wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu7yMZ"
+		_, err := ParseIDWithDiagnostics(typo)
+		var parseErr *IDParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, IDParseErrorChecksumMismatch, parseErr.Kind)
+		require.Contains(t, parseErr.Suggestions, want)
+		for _, s := range parseErr.Suggestions {
+			sID, err := IDFromString(s)
+			require.NoError(t, err)
+			_, err = ParseDIDFromID(sID)
+			require.NoError(t, err)
+		}
+	})
+}
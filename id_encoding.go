@@ -0,0 +1,162 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/mr-tron/base58"
+)
+
+// MultibaseEncoding is a multibase prefix of the encoding
+// https://github.com/multiformats/multibase
+type MultibaseEncoding byte
+
+const (
+	// MultibaseBase58BTC is base58 with bitcoin alphabet, the same encoding
+	// as ID.String
+	MultibaseBase58BTC MultibaseEncoding = 'z'
+	// MultibaseBase16 is lowercase hex
+	MultibaseBase16 MultibaseEncoding = 'f'
+	// MultibaseBase16Upper is uppercase hex
+	MultibaseBase16Upper MultibaseEncoding = 'F'
+	// MultibaseBase64URL is URL-safe base64 without padding
+	MultibaseBase64URL MultibaseEncoding = 'u'
+)
+
+var (
+	// ErrUnsupportedMultibase returned for unknown multibase prefixes
+	ErrUnsupportedMultibase = errors.New("unsupported multibase encoding")
+	// ErrAmbiguousIDFormat returned by IDFromAny when input decodes to
+	// different valid IDs in different formats
+	ErrAmbiguousIDFormat = errors.New("ambiguous ID format")
+)
+
+// Hex returns hex representation of ID bytes
+func (id *ID) Hex() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Decimal returns decimal representation of ID as it is used in circuits and
+// smart contracts
+func (id *ID) Decimal() string {
+	return id.BigInt().Text(10)
+}
+
+// Multibase returns multibase representation of ID bytes
+func (id *ID) Multibase(enc MultibaseEncoding) (string, error) {
+	var s string
+	switch enc {
+	case MultibaseBase58BTC:
+		s = base58.Encode(id[:])
+	case MultibaseBase16:
+		s = hex.EncodeToString(id[:])
+	case MultibaseBase16Upper:
+		s = strings.ToUpper(hex.EncodeToString(id[:]))
+	case MultibaseBase64URL:
+		s = base64.RawURLEncoding.EncodeToString(id[:])
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedMultibase, rune(enc))
+	}
+	return string(enc) + s, nil
+}
+
+// IDFromHex returns the ID from hex representation of its bytes. The 0x
+// prefix is optional.
+func IDFromHex(s string) (ID, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return ID{}, err
+	}
+	return IDFromBytes(b)
+}
+
+// IDFromDecimal returns the ID from its decimal representation
+func IDFromDecimal(s string) (ID, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok || i.Sign() < 0 {
+		return ID{}, errors.New("IDFromDecimal error: invalid decimal string")
+	}
+	return IDFromInt(i)
+}
+
+// IDFromMultibase returns the ID from its multibase representation
+func IDFromMultibase(s string) (ID, error) {
+	if s == "" {
+		return ID{}, fmt.Errorf("%w: empty string", ErrUnsupportedMultibase)
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	switch MultibaseEncoding(s[0]) {
+	case MultibaseBase58BTC:
+		b, err = base58.Decode(s[1:])
+	case MultibaseBase16, MultibaseBase16Upper:
+		b, err = hex.DecodeString(s[1:])
+	case MultibaseBase64URL:
+		b, err = base64.RawURLEncoding.DecodeString(s[1:])
+	default:
+		return ID{}, fmt.Errorf("%w: %q", ErrUnsupportedMultibase, s[0])
+	}
+	if err != nil {
+		return ID{}, err
+	}
+	return IDFromBytes(b)
+}
+
+// IDFromAny detects the format of the ID string and parses it. Supported
+// formats are base58 (ID.String), hex, decimal and multibase. Every format
+// is checked against the ID checksum. If the string is a valid ID in more
+// than one format, ErrAmbiguousIDFormat is returned.
+func IDFromAny(s string) (ID, error) {
+	parsers := []func(string) (ID, error){IDFromString}
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") ||
+		len(s) == hex.EncodedLen(idLength) {
+
+		parsers = append(parsers, IDFromHex)
+	}
+	if s != "" && strings.Trim(s, "0123456789") == "" {
+		parsers = append(parsers, IDFromDecimal)
+	}
+	if s != "" {
+		switch MultibaseEncoding(s[0]) {
+		case MultibaseBase58BTC, MultibaseBase16, MultibaseBase16Upper,
+			MultibaseBase64URL:
+
+			parsers = append(parsers, IDFromMultibase)
+		}
+	}
+
+	var (
+		found   []ID
+		lastErr error
+	)
+	for _, parse := range parsers {
+		id, err := parse(s)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(found) == 0 || found[0] != id {
+			found = append(found, id)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return ID{}, fmt.Errorf("can't parse ID %q in any format: %w", s,
+			lastErr)
+	case 1:
+		return found[0], nil
+	default:
+		return ID{}, fmt.Errorf("%w: %q", ErrAmbiguousIDFormat, s)
+	}
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestID_TextEncodings(t *testing.T) {
+	id, err := IDFromString("11BBCPZ6Zq9HX1JhHrHT3QKUFD9kFDEyJFoAVMptVs")
+	require.NoError(t, err)
+
+	hexStr := id.Hex()
+	require.Len(t, hexStr, 62)
+	id2, err := IDFromHex(hexStr)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+	id2, err = IDFromHex("0x" + hexStr)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+
+	dec := id.Decimal()
+	require.Equal(t, id.BigInt().String(), dec)
+	id2, err = IDFromDecimal(dec)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+
+	_, err = IDFromDecimal("-1")
+	require.EqualError(t, err, "IDFromDecimal error: invalid decimal string")
+
+	// corrupted checksum is detected in every format
+	corrupted := id
+	corrupted[30]++
+	_, err = IDFromHex(corrupted.Hex())
+	require.EqualError(t, err, "IDFromBytes error: checksum error")
+	_, err = IDFromDecimal(corrupted.Decimal())
+	require.EqualError(t, err, "IDFromBytes error: checksum error")
+}
+
+func TestID_Multibase(t *testing.T) {
+	id, err := IDFromString("11BBCPZ6Zq9HX1JhHrHT3QKUFD9kFDEyJFoAVMptVs")
+	require.NoError(t, err)
+
+	mb, err := id.Multibase(MultibaseBase58BTC)
+	require.NoError(t, err)
+	require.Equal(t, "z11BBCPZ6Zq9HX1JhHrHT3QKUFD9kFDEyJFoAVMptVs", mb)
+
+	for _, enc := range []MultibaseEncoding{MultibaseBase58BTC,
+		MultibaseBase16, MultibaseBase16Upper, MultibaseBase64URL} {
+
+		mb, err = id.Multibase(enc)
+		require.NoError(t, err)
+		require.Equal(t, byte(enc), mb[0])
+		id2, err := IDFromMultibase(mb)
+		require.NoError(t, err)
+		require.Equal(t, id, id2)
+	}
+
+	_, err = id.Multibase('m')
+	require.ErrorIs(t, err, ErrUnsupportedMultibase)
+	_, err = IDFromMultibase("m1234")
+	require.ErrorIs(t, err, ErrUnsupportedMultibase)
+	_, err = IDFromMultibase("")
+	require.ErrorIs(t, err, ErrUnsupportedMultibase)
+}
+
+func TestIDFromAny(t *testing.T) {
+	id, err := IDFromString("11BBCPZ6Zq9HX1JhHrHT3QKUFD9kFDEyJFoAVMptVs")
+	require.NoError(t, err)
+
+	mb58, err := id.Multibase(MultibaseBase58BTC)
+	require.NoError(t, err)
+	mb64, err := id.Multibase(MultibaseBase64URL)
+	require.NoError(t, err)
+
+	for _, in := range []string{id.String(), id.Hex(), "0x" + id.Hex(),
+		id.Decimal(), mb58, mb64} {
+
+		id2, err := IDFromAny(in)
+		require.NoError(t, err, in)
+		require.Equal(t, id, id2, in)
+	}
+
+	_, err = IDFromAny("not an ID")
+	require.Error(t, err)
+	_, err = IDFromAny("")
+	require.Error(t, err)
+}
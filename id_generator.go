@@ -0,0 +1,117 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// ErrIDPrefixNotFound returned by IDGenerator when no ID with requested
+// prefix was found in the given number of attempts
+var ErrIDPrefixNotFound = errors.New("ID with prefix not found")
+
+// IDGenerator generates valid IDs with pseudo-random genesis derived from
+// the seed. The same seed produces the same sequence of IDs, so generator
+// may be used to make stable test fixtures. IDs are not backed by any real
+// identity state. IDGenerator is not safe for concurrent use.
+type IDGenerator struct {
+	seed    [sha256.Size]byte
+	counter uint64
+}
+
+// NewIDGenerator creates new IDGenerator from the seed
+func NewIDGenerator(seed []byte) *IDGenerator {
+	return &IDGenerator{seed: sha256.Sum256(seed)}
+}
+
+// NewID returns the next ID of the DID type. The type must be registered and
+// must not be the type of unsupported DIDs.
+func (g *IDGenerator) NewID(typ DIDType) (ID, error) {
+	err := checkGeneratorDIDType(typ)
+	if err != nil {
+		return ID{}, err
+	}
+	return NewID(typ, g.nextGenesis()), nil
+}
+
+// NewDID returns DID of the next ID of the DID type
+func (g *IDGenerator) NewDID(typ DIDType) (*w3c.DID, error) {
+	id, err := g.NewID(typ)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDIDFromID(id)
+}
+
+// NewIDWithPrefix returns the next ID of the DID type which base58 string
+// starts with the prefix. Leading characters of ID string depend on the DID
+// type, so the prefix should start with them. Each character of the prefix
+// after those multiplies expected number of attempts by about 58. Returns
+// ErrIDPrefixNotFound if no ID found in maxAttempts.
+func (g *IDGenerator) NewIDWithPrefix(typ DIDType, prefix string,
+	maxAttempts int) (ID, error) {
+
+	err := checkGeneratorDIDType(typ)
+	if err != nil {
+		return ID{}, err
+	}
+	if maxAttempts <= 0 {
+		return ID{}, errors.New("maxAttempts must be positive")
+	}
+	for i, c := range prefix {
+		if !strings.ContainsRune(base58Alphabet, c) {
+			return ID{}, fmt.Errorf(
+				"invalid prefix: %q at position %v is not base58 character",
+				c, i)
+		}
+	}
+
+	for i := 0; i < maxAttempts; i++ {
+		id := NewID(typ, g.nextGenesis())
+		if strings.HasPrefix(id.String(), prefix) {
+			return id, nil
+		}
+	}
+	return ID{}, fmt.Errorf("%w: %q in %v attempts", ErrIDPrefixNotFound,
+		prefix, maxAttempts)
+}
+
+// NewDIDWithPrefix returns DID of the next ID of the DID type which base58
+// string starts with the prefix. See NewIDWithPrefix.
+func (g *IDGenerator) NewDIDWithPrefix(typ DIDType, prefix string,
+	maxAttempts int) (*w3c.DID, error) {
+
+	id, err := g.NewIDWithPrefix(typ, prefix, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDIDFromID(id)
+}
+
+func (g *IDGenerator) nextGenesis() [genesisLn]byte {
+	var buf [sha256.Size + 8]byte
+	copy(buf[:], g.seed[:])
+	binary.BigEndian.PutUint64(buf[sha256.Size:], g.counter)
+	g.counter++
+
+	h := sha256.Sum256(buf[:])
+	var genesis [genesisLn]byte
+	copy(genesis[:], h[:])
+	return genesis
+}
+
+func checkGeneratorDIDType(typ DIDType) error {
+	method, blockchain, networkID, err := typ.decode()
+	if err != nil {
+		return err
+	}
+	if isUnsupportedDID(method, blockchain, networkID) {
+		return fmt.Errorf("%w: can't generate ID of unsupported DID type",
+			ErrUnsupportedID)
+	}
+	return nil
+}
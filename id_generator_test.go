@@ -0,0 +1,85 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDGenerator(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, Polygon, Amoy)
+	require.NoError(t, err)
+
+	g1 := NewIDGenerator([]byte("seed"))
+	g2 := NewIDGenerator([]byte("seed"))
+
+	id1, err := g1.NewID(typ)
+	require.NoError(t, err)
+	require.True(t, CheckChecksum(id1))
+	require.Equal(t, typ, id1.Type())
+
+	id2, err := g2.NewID(typ)
+	require.NoError(t, err)
+	require.Equal(t, id1, id2)
+
+	id3, err := g1.NewID(typ)
+	require.NoError(t, err)
+	require.NotEqual(t, id1, id3)
+
+	other, err := NewIDGenerator([]byte("other seed")).NewID(typ)
+	require.NoError(t, err)
+	require.NotEqual(t, id1, other)
+
+	did, err := g1.NewDID(typ)
+	require.NoError(t, err)
+	require.Equal(t, "did:iden3:polygon:amoy:", did.String()[:23])
+	_, err = IDFromDID(*did)
+	require.NoError(t, err)
+
+	// version 2 flag of the type does not select the checksum
+	typV2, err := typ.WithIDVersion(IDVersion2)
+	require.NoError(t, err)
+	idV2, err := g1.NewID(typV2)
+	require.NoError(t, err)
+	v, err := IDVersionFromID(idV2)
+	require.NoError(t, err)
+	require.Equal(t, IDVersion1, v)
+
+	_, err = g1.NewID(DIDType{0xff, 0xff})
+	require.Error(t, err)
+
+	_, err = g1.NewID(DIDType{DIDMethodByte[DIDMethodOther], 0xff})
+	require.ErrorIs(t, err, ErrUnsupportedID)
+}
+
+func TestIDGenerator_NewIDWithPrefix(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, Polygon, Amoy)
+	require.NoError(t, err)
+
+	g := NewIDGenerator([]byte("seed"))
+	first, err := g.NewID(typ)
+	require.NoError(t, err)
+	prefix := first.String()[:3]
+
+	id, err := NewIDGenerator([]byte("seed")).NewIDWithPrefix(typ, prefix,
+		1000)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(id.String(), prefix))
+
+	did, err := NewIDGenerator([]byte("seed")).NewDIDWithPrefix(typ, prefix,
+		1000)
+	require.NoError(t, err)
+	require.Equal(t, id.String(), did.IDStrings[2])
+
+	_, err = g.NewIDWithPrefix(typ, "11111", 10)
+	require.True(t, errors.Is(err, ErrIDPrefixNotFound))
+
+	_, err = g.NewIDWithPrefix(typ, "x0", 10)
+	require.EqualError(t, err,
+		"invalid prefix: '0' at position 1 is not base58 character")
+
+	_, err = g.NewIDWithPrefix(typ, "x", 0)
+	require.EqualError(t, err, "maxAttempts must be positive")
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// IDOrder is an ordering of IDs
+type IDOrder uint8
+
+const (
+	// IDOrderBytes orders IDs lexicographically by their bytes, i.e. by DID
+	// type first and then by genesis and checksum. The same order as of
+	// ID.Compare.
+	IDOrderBytes IDOrder = iota
+	// IDOrderNumeric orders IDs by numeric value of ID.BigInt, the order IDs
+	// take in circuits and smart contracts. ID bytes are little-endian, so
+	// the last byte is the most significant. The same order as of
+	// ID.CompareNumeric.
+	IDOrderNumeric
+)
+
+// Compare returns an integer comparing two IDs in byte order. The result is
+// 0 if id == id2, -1 if id < id2 and +1 if id > id2.
+func (id *ID) Compare(id2 *ID) int {
+	return bytes.Compare(id[:], id2[:])
+}
+
+// CompareNumeric returns an integer comparing numeric values of two IDs as
+// returned by ID.BigInt. The result is 0 if id == id2, -1 if id < id2 and +1
+// if id > id2.
+func (id *ID) CompareNumeric(id2 *ID) int {
+	for i := len(id) - 1; i >= 0; i-- {
+		switch {
+		case id[i] < id2[i]:
+			return -1
+		case id[i] > id2[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// SortIDs sorts IDs in place in the given order
+func SortIDs(ids []ID, order IDOrder) {
+	cmp := idComparator(order)
+	sort.Slice(ids, func(i, j int) bool {
+		return cmp(&ids[i], &ids[j]) < 0
+	})
+}
+
+// SortDIDs sorts DIDs in place by their IDs in the given order. DIDs of the
+// same identity keep their relative order. Returns error if ID can't be
+// obtained from any of DIDs, in this case dids are not modified.
+func SortDIDs(dids []w3c.DID, order IDOrder) error {
+	ids := make([]ID, len(dids))
+	for i := range dids {
+		var err error
+		ids[i], err = IDFromDID(dids[i])
+		if err != nil {
+			return fmt.Errorf("can't sort DID %v: %w", dids[i].String(), err)
+		}
+	}
+
+	cmp := idComparator(order)
+	sort.Stable(&didsByID{dids: dids, ids: ids, cmp: cmp})
+	return nil
+}
+
+func idComparator(order IDOrder) func(id, id2 *ID) int {
+	if order == IDOrderNumeric {
+		return (*ID).CompareNumeric
+	}
+	return (*ID).Compare
+}
+
+type didsByID struct {
+	dids []w3c.DID
+	ids  []ID
+	cmp  func(id, id2 *ID) int
+}
+
+func (s *didsByID) Len() int { return len(s.dids) }
+
+func (s *didsByID) Less(i, j int) bool {
+	return s.cmp(&s.ids[i], &s.ids[j]) < 0
+}
+
+func (s *didsByID) Swap(i, j int) {
+	s.dids[i], s.dids[j] = s.dids[j], s.dids[i]
+	s.ids[i], s.ids[j] = s.ids[j], s.ids[i]
+}
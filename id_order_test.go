@@ -0,0 +1,78 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestID_Compare(t *testing.T) {
+	id1 := ID{0: 1, 30: 2}
+	id2 := ID{0: 2, 30: 1}
+
+	require.Equal(t, 0, id1.Compare(&ID{0: 1, 30: 2}))
+	require.Equal(t, -1, id1.Compare(&id2))
+	require.Equal(t, 1, id2.Compare(&id1))
+
+	require.Equal(t, 0, id1.CompareNumeric(&ID{0: 1, 30: 2}))
+	require.Equal(t, 1, id1.CompareNumeric(&id2))
+	require.Equal(t, -1, id2.CompareNumeric(&id1))
+	require.Equal(t, id1.BigInt().Cmp(id2.BigInt()), id1.CompareNumeric(&id2))
+}
+
+func TestSortIDs(t *testing.T) {
+	ids := helperIDsFromStates(t, 5, 1, 4, 2, 3)
+
+	byBytes := append([]ID(nil), ids...)
+	SortIDs(byBytes, IDOrderBytes)
+	for i := 1; i < len(byBytes); i++ {
+		require.Equal(t, -1, byBytes[i-1].Compare(&byBytes[i]))
+	}
+
+	numeric := append([]ID(nil), ids...)
+	SortIDs(numeric, IDOrderNumeric)
+	for i := 1; i < len(numeric); i++ {
+		require.Equal(t, -1,
+			numeric[i-1].BigInt().Cmp(numeric[i].BigInt()))
+	}
+}
+
+func TestSortDIDs(t *testing.T) {
+	ids := helperIDsFromStates(t, 3, 1, 2)
+	dids := make([]w3c.DID, 0, len(ids)+1)
+	for i := range ids {
+		did, err := ParseDIDFromID(ids[i])
+		require.NoError(t, err)
+		dids = append(dids, *did)
+	}
+	withFragment := dids[0]
+	withFragment.Fragment = "key-1"
+	dids = append(dids, withFragment)
+
+	err := SortDIDs(dids, IDOrderNumeric)
+	require.NoError(t, err)
+
+	var prev *big.Int
+	for i := range dids {
+		id, err := IDFromDID(dids[i])
+		require.NoError(t, err)
+		if prev != nil {
+			require.True(t, prev.Cmp(id.BigInt()) <= 0)
+		}
+		prev = id.BigInt()
+	}
+	// DIDs of the same identity keep their relative order
+	for i := range dids {
+		if dids[i].Fragment == "key-1" {
+			require.Equal(t, withFragment.ID, dids[i-1].ID)
+			require.Equal(t, "", dids[i-1].Fragment)
+		}
+	}
+
+	bad := []w3c.DID{{Method: "iden3", ID: "invalid",
+		IDStrings: []string{"invalid"}}}
+	err = SortDIDs(bad, IDOrderBytes)
+	require.Error(t, err)
+}
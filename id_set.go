@@ -0,0 +1,388 @@
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// IDSet is a set of IDs. Iteration and JSON representation are sorted in
+// byte order of IDs. Zero value is an empty set ready to use.
+// IDSet is not safe for concurrent use.
+type IDSet struct {
+	m map[ID]struct{}
+}
+
+// NewIDSet creates new IDSet with given IDs
+func NewIDSet(ids ...ID) *IDSet {
+	s := &IDSet{m: make(map[ID]struct{}, len(ids))}
+	s.Add(ids...)
+	return s
+}
+
+// Add adds IDs to the set
+func (s *IDSet) Add(ids ...ID) {
+	if s.m == nil {
+		s.m = make(map[ID]struct{}, len(ids))
+	}
+	for _, id := range ids {
+		s.m[id] = struct{}{}
+	}
+}
+
+// Remove removes IDs from the set
+func (s *IDSet) Remove(ids ...ID) {
+	if s == nil {
+		return
+	}
+	for _, id := range ids {
+		delete(s.m, id)
+	}
+}
+
+// Contains returns true if ID is in the set
+func (s *IDSet) Contains(id ID) bool {
+	_, ok := s.elems()[id]
+	return ok
+}
+
+// Len returns the number of IDs in the set
+func (s *IDSet) Len() int {
+	return len(s.elems())
+}
+
+// Union returns new set with IDs that are in either set. Nil set is empty.
+func (s *IDSet) Union(other *IDSet) *IDSet {
+	res := NewIDSet()
+	for id := range s.elems() {
+		res.m[id] = struct{}{}
+	}
+	for id := range other.elems() {
+		res.m[id] = struct{}{}
+	}
+	return res
+}
+
+// Intersection returns new set with IDs that are in both sets. Nil set is
+// empty.
+func (s *IDSet) Intersection(other *IDSet) *IDSet {
+	small, big := s, other
+	if small.Len() > big.Len() {
+		small, big = big, small
+	}
+	res := NewIDSet()
+	for id := range small.elems() {
+		if big.Contains(id) {
+			res.m[id] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference returns new set with IDs that are in s but not in other. Nil
+// set is empty.
+func (s *IDSet) Difference(other *IDSet) *IDSet {
+	res := NewIDSet()
+	for id := range s.elems() {
+		if !other.Contains(id) {
+			res.m[id] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Sorted returns IDs of the set in byte order
+func (s *IDSet) Sorted() []ID {
+	return sortedIDKeys(s.elems())
+}
+
+// elems returns the elements of the set, nil set has no elements
+func (s *IDSet) elems() map[ID]struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.m
+}
+
+// Range calls fn for each ID in byte order until fn returns false
+func (s *IDSet) Range(fn func(id ID) bool) {
+	for _, id := range s.Sorted() {
+		if !fn(id) {
+			return
+		}
+	}
+}
+
+// MarshalJSON returns JSON array of base58 IDs in byte order
+func (s IDSet) MarshalJSON() ([]byte, error) {
+	ids := s.Sorted()
+	if ids == nil {
+		ids = []ID{}
+	}
+	return json.Marshal(ids)
+}
+
+// UnmarshalJSON parses JSON array of base58 IDs
+func (s *IDSet) UnmarshalJSON(b []byte) error {
+	var ids []ID
+	err := json.Unmarshal(b, &ids)
+	if err != nil {
+		return err
+	}
+	*s = *NewIDSet(ids...)
+	return nil
+}
+
+// IDMap is a map keyed by ID. Iteration is sorted in byte order of IDs.
+// Zero value is an empty map ready to use. IDMap is not safe for concurrent
+// use.
+type IDMap[T any] struct {
+	m map[ID]T
+}
+
+// NewIDMap creates new empty IDMap
+func NewIDMap[T any]() *IDMap[T] {
+	return &IDMap[T]{m: make(map[ID]T)}
+}
+
+// Set sets the value for ID
+func (m *IDMap[T]) Set(id ID, v T) {
+	if m.m == nil {
+		m.m = make(map[ID]T)
+	}
+	m.m[id] = v
+}
+
+// Get returns the value for ID and true if ID is in the map
+func (m *IDMap[T]) Get(id ID) (T, bool) {
+	v, ok := m.elems()[id]
+	return v, ok
+}
+
+// Delete removes ID from the map
+func (m *IDMap[T]) Delete(id ID) {
+	if m == nil {
+		return
+	}
+	delete(m.m, id)
+}
+
+// Len returns the number of IDs in the map
+func (m *IDMap[T]) Len() int {
+	return len(m.elems())
+}
+
+// Keys returns IDs of the map in byte order
+func (m *IDMap[T]) Keys() []ID {
+	return sortedIDKeys(m.elems())
+}
+
+// KeySet returns IDs of the map as IDSet
+func (m *IDMap[T]) KeySet() *IDSet {
+	s := &IDSet{m: make(map[ID]struct{}, m.Len())}
+	for id := range m.elems() {
+		s.m[id] = struct{}{}
+	}
+	return s
+}
+
+// Range calls fn for each ID and value in byte order of IDs until fn
+// returns false
+func (m *IDMap[T]) Range(fn func(id ID, v T) bool) {
+	elems := m.elems()
+	for _, id := range m.Keys() {
+		if !fn(id, elems[id]) {
+			return
+		}
+	}
+}
+
+// elems returns the entries of the map, nil map has no entries
+func (m *IDMap[T]) elems() map[ID]T {
+	if m == nil {
+		return nil
+	}
+	return m.m
+}
+
+// MarshalJSON returns JSON object keyed by base58 IDs
+func (m IDMap[T]) MarshalJSON() ([]byte, error) {
+	if m.m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m.m)
+}
+
+// UnmarshalJSON parses JSON object keyed by base58 IDs
+func (m *IDMap[T]) UnmarshalJSON(b []byte) error {
+	var mm map[ID]T
+	err := json.Unmarshal(b, &mm)
+	if err != nil {
+		return err
+	}
+	if mm == nil {
+		mm = make(map[ID]T)
+	}
+	m.m = mm
+	return nil
+}
+
+// DIDSet is a set of DIDs. DIDs are normalized to IDs with IDFromDID, so DID
+// URLs of the same identity are the same element of the set. The first added
+// DID of each identity is kept for output. Zero value is an empty set ready
+// to use. DIDSet is not safe for concurrent use.
+type DIDSet struct {
+	m map[ID]w3c.DID
+}
+
+// NewDIDSet creates new DIDSet with given DIDs
+func NewDIDSet(dids ...w3c.DID) (*DIDSet, error) {
+	s := &DIDSet{m: make(map[ID]w3c.DID, len(dids))}
+	for _, did := range dids {
+		err := s.Add(did)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds DID to the set
+func (s *DIDSet) Add(did w3c.DID) error {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return err
+	}
+	if s.m == nil {
+		s.m = make(map[ID]w3c.DID)
+	}
+	if _, ok := s.m[id]; !ok {
+		s.m[id] = did
+	}
+	return nil
+}
+
+// Remove removes DID from the set
+func (s *DIDSet) Remove(did w3c.DID) error {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+	delete(s.m, id)
+	return nil
+}
+
+// Contains returns true if the identity of DID is in the set
+func (s *DIDSet) Contains(did w3c.DID) (bool, error) {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return false, err
+	}
+	_, ok := s.elems()[id]
+	return ok, nil
+}
+
+// Len returns the number of DIDs in the set
+func (s *DIDSet) Len() int {
+	return len(s.elems())
+}
+
+// IDs returns IDs of the set
+func (s *DIDSet) IDs() *IDSet {
+	ids := &IDSet{m: make(map[ID]struct{}, s.Len())}
+	for id := range s.elems() {
+		ids.m[id] = struct{}{}
+	}
+	return ids
+}
+
+// Union returns new set with DIDs that are in either set. Nil set is empty.
+func (s *DIDSet) Union(other *DIDSet) *DIDSet {
+	res := &DIDSet{m: make(map[ID]w3c.DID, s.Len())}
+	for id, did := range s.elems() {
+		res.m[id] = did
+	}
+	for id, did := range other.elems() {
+		if _, ok := res.m[id]; !ok {
+			res.m[id] = did
+		}
+	}
+	return res
+}
+
+// Intersection returns new set with DIDs that are in both sets. Nil set is
+// empty.
+func (s *DIDSet) Intersection(other *DIDSet) *DIDSet {
+	res := &DIDSet{m: make(map[ID]w3c.DID)}
+	for id, did := range s.elems() {
+		if _, ok := other.elems()[id]; ok {
+			res.m[id] = did
+		}
+	}
+	return res
+}
+
+// Difference returns new set with DIDs that are in s but not in other. Nil
+// set is empty.
+func (s *DIDSet) Difference(other *DIDSet) *DIDSet {
+	res := &DIDSet{m: make(map[ID]w3c.DID)}
+	for id, did := range s.elems() {
+		if _, ok := other.elems()[id]; !ok {
+			res.m[id] = did
+		}
+	}
+	return res
+}
+
+// Sorted returns DIDs of the set in byte order of their IDs
+func (s *DIDSet) Sorted() []w3c.DID {
+	ids := sortedIDKeys(s.elems())
+	dids := make([]w3c.DID, len(ids))
+	for i, id := range ids {
+		dids[i] = s.m[id]
+	}
+	return dids
+}
+
+// elems returns the elements of the set, nil set has no elements
+func (s *DIDSet) elems() map[ID]w3c.DID {
+	if s == nil {
+		return nil
+	}
+	return s.m
+}
+
+// MarshalJSON returns JSON array of DIDs in byte order of their IDs
+func (s DIDSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Sorted())
+}
+
+// UnmarshalJSON parses JSON array of DIDs
+func (s *DIDSet) UnmarshalJSON(b []byte) error {
+	var dids []w3c.DID
+	err := json.Unmarshal(b, &dids)
+	if err != nil {
+		return err
+	}
+	s2, err := NewDIDSet(dids...)
+	if err != nil {
+		return err
+	}
+	*s = *s2
+	return nil
+}
+
+func sortedIDKeys[T any](m map[ID]T) []ID {
+	if len(m) == 0 {
+		return nil
+	}
+	ids := make([]ID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	SortIDs(ids, IDOrderBytes)
+	return ids
+}
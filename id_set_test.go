@@ -0,0 +1,228 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func helperIDsFromStates(t testing.TB, states ...int64) []ID {
+	t.Helper()
+
+	typ, err := BuildDIDType(DIDMethodIden3, Polygon, Amoy)
+	require.NoError(t, err)
+
+	ids := make([]ID, len(states))
+	for i, s := range states {
+		// only high bytes of state go to genesis
+		state := new(big.Int).Lsh(big.NewInt(s), 200)
+		id, err := NewIDFromIdenState(typ, state)
+		require.NoError(t, err)
+		ids[i] = *id
+	}
+	return ids
+}
+
+func TestIDSet(t *testing.T) {
+	ids := helperIDsFromStates(t, 1, 2, 3, 4)
+
+	var zero IDSet
+	require.Equal(t, 0, zero.Len())
+	require.False(t, zero.Contains(ids[0]))
+	zero.Add(ids[0])
+	require.True(t, zero.Contains(ids[0]))
+
+	a := NewIDSet(ids[0], ids[1], ids[2], ids[0])
+	b := NewIDSet(ids[1], ids[2], ids[3])
+	require.Equal(t, 3, a.Len())
+
+	require.Equal(t, NewIDSet(ids...).Sorted(), a.Union(b).Sorted())
+	require.Equal(t, NewIDSet(ids[1], ids[2]).Sorted(),
+		a.Intersection(b).Sorted())
+	require.Equal(t, []ID{ids[0]}, a.Difference(b).Sorted())
+	require.Nil(t, NewIDSet().Intersection(a).Sorted())
+
+	a.Remove(ids[0])
+	require.False(t, a.Contains(ids[0]))
+	require.Equal(t, 2, a.Len())
+
+	sorted := NewIDSet(ids...).Sorted()
+	for i := 1; i < len(sorted); i++ {
+		require.Less(t, string(sorted[i-1][:]), string(sorted[i][:]))
+	}
+
+	var visited []ID
+	NewIDSet(ids...).Range(func(id ID) bool {
+		visited = append(visited, id)
+		return len(visited) < 2
+	})
+	require.Equal(t, sorted[:2], visited)
+}
+
+func TestIDSet_JSON(t *testing.T) {
+	ids := helperIDsFromStates(t, 1, 2, 3)
+	s := NewIDSet(ids...)
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	sorted := s.Sorted()
+	want, err := json.Marshal([]string{sorted[0].String(),
+		sorted[1].String(), sorted[2].String()})
+	require.NoError(t, err)
+	require.JSONEq(t, string(want), string(b))
+
+	var s2 IDSet
+	err = json.Unmarshal(b, &s2)
+	require.NoError(t, err)
+	require.Equal(t, sorted, s2.Sorted())
+
+	b, err = json.Marshal(IDSet{})
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(b))
+
+	err = json.Unmarshal([]byte(`["invalid"]`), &s2)
+	require.Error(t, err)
+}
+
+func TestIDMap(t *testing.T) {
+	ids := helperIDsFromStates(t, 1, 2, 3)
+
+	var m IDMap[int]
+	m.Set(ids[0], 1)
+	m.Set(ids[1], 2)
+	m.Set(ids[2], 3)
+	m.Set(ids[0], 10)
+	require.Equal(t, 3, m.Len())
+
+	v, ok := m.Get(ids[0])
+	require.True(t, ok)
+	require.Equal(t, 10, v)
+
+	m.Delete(ids[1])
+	_, ok = m.Get(ids[1])
+	require.False(t, ok)
+	require.Equal(t, NewIDSet(ids[0], ids[2]).Sorted(), m.Keys())
+	require.Equal(t, m.Keys(), m.KeySet().Sorted())
+
+	var values []int
+	m.Range(func(id ID, v int) bool {
+		values = append(values, v)
+		return true
+	})
+	require.Len(t, values, 2)
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"`+ids[0].String()+`":10,"`+ids[2].String()+`":3}`, string(b))
+
+	m2 := NewIDMap[int]()
+	err = json.Unmarshal(b, m2)
+	require.NoError(t, err)
+	require.Equal(t, m.Keys(), m2.Keys())
+	v, ok = m2.Get(ids[2])
+	require.True(t, ok)
+	require.Equal(t, 3, v)
+}
+
+func TestIDSet_Nil(t *testing.T) {
+	ids := helperIDsFromStates(t, 1, 2)
+	a := NewIDSet(ids...)
+
+	var nilSet *IDSet
+	require.Equal(t, 0, nilSet.Len())
+	require.False(t, nilSet.Contains(ids[0]))
+	require.Equal(t, a.Sorted(), a.Union(nil).Sorted())
+	require.Equal(t, a.Sorted(), nilSet.Union(a).Sorted())
+	require.Nil(t, a.Intersection(nil).Sorted())
+	require.Nil(t, nilSet.Intersection(a).Sorted())
+	require.Equal(t, a.Sorted(), a.Difference(nil).Sorted())
+	require.Nil(t, nilSet.Difference(a).Sorted())
+	nilSet.Remove(ids...)
+	nilSet.Range(func(ID) bool {
+		t.Fatal("unexpected element of nil set")
+		return true
+	})
+}
+
+func TestIDMap_Nil(t *testing.T) {
+	ids := helperIDsFromStates(t, 1)
+
+	var m *IDMap[int]
+	require.Equal(t, 0, m.Len())
+	_, ok := m.Get(ids[0])
+	require.False(t, ok)
+	require.Nil(t, m.Keys())
+	require.Equal(t, 0, m.KeySet().Len())
+	m.Delete(ids[0])
+	m.Range(func(ID, int) bool {
+		t.Fatal("unexpected entry of nil map")
+		return true
+	})
+}
+
+func TestDIDSet_Nil(t *testing.T) {
+	did := helperBuildDIDFromType(t, DIDMethodIden3, Polygon, Amoy)
+	s, err := NewDIDSet(*did)
+	require.NoError(t, err)
+
+	var nilSet *DIDSet
+	require.Equal(t, 0, nilSet.Len())
+	ok, err := nilSet.Contains(*did)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 0, nilSet.IDs().Len())
+	require.Equal(t, s.Sorted(), s.Union(nil).Sorted())
+	require.Equal(t, s.Sorted(), nilSet.Union(s).Sorted())
+	require.Equal(t, 0, s.Intersection(nil).Len())
+	require.Equal(t, 0, nilSet.Intersection(s).Len())
+	require.Equal(t, s.Sorted(), s.Difference(nil).Sorted())
+	require.Equal(t, 0, nilSet.Difference(s).Len())
+	require.NoError(t, nilSet.Remove(*did))
+}
+
+func TestDIDSet(t *testing.T) {
+	did1, err := w3c.ParseDID(
+		"did:iden3:polygon:mumbai:wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+	did1URL, err := w3c.ParseDID(
+		"did:iden3:polygon:mumbai:wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ#key-1")
+	require.NoError(t, err)
+	did2 := helperBuildDIDFromType(t, DIDMethodIden3, Polygon, Amoy)
+	did3, err := w3c.ParseDID("did:example:123")
+	require.NoError(t, err)
+
+	s, err := NewDIDSet(*did1, *did1URL, *did2)
+	require.NoError(t, err)
+	require.Equal(t, 2, s.Len())
+
+	ok, err := s.Contains(*did1URL)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	other, err := NewDIDSet(*did2, *did3)
+	require.NoError(t, err)
+	require.Equal(t, 3, s.Union(other).Len())
+	require.Equal(t, []w3c.DID{*did2}, s.Intersection(other).Sorted())
+	require.Equal(t, []w3c.DID{*did1}, s.Difference(other).Sorted())
+
+	id1, err := IDFromDID(*did1)
+	require.NoError(t, err)
+	require.True(t, s.IDs().Contains(id1))
+
+	b, err := json.Marshal(s.Union(other))
+	require.NoError(t, err)
+	var s2 DIDSet
+	err = json.Unmarshal(b, &s2)
+	require.NoError(t, err)
+	require.Equal(t, s.Union(other).Sorted(), s2.Sorted())
+
+	err = s.Remove(*did1URL)
+	require.NoError(t, err)
+	ok, err = s.Contains(*did1)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -239,5 +239,5 @@ func TestID_Type(t *testing.T) {
 	id, err := IDFromString("1MWtoAdZESeiphxp3bXupZcfS9DhMTdWNSjRwVYc2")
 	require.NoError(t, err)
 
-	require.Equal(t, id.Type(), [2]byte{0x00, 0x01})
+	require.Equal(t, id.Type(), DIDType{0x00, 0x01})
 }
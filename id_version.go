@@ -0,0 +1,136 @@
+package core
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// IDVersion is a version of ID encoding. Versions differ by the checksum
+// scheme.
+type IDVersion uint8
+
+const (
+	// IDVersion1 is the original ID encoding with 16-bit byte sum checksum
+	IDVersion1 IDVersion = 1
+	// IDVersion2 is the ID encoding with checksum equal to truncated SHA-256
+	// hash of type and genesis. It detects permutations and substitutions of
+	// genesis bytes that the byte sum checksum misses.
+	IDVersion2 IDVersion = 2
+)
+
+// idVersion2Flag is set in the DID method byte of version 2 IDs
+const idVersion2Flag byte = 0b1000_0000
+
+// ErrIDVersionNotSupported returned when ID version can't be used for the
+// DID type.
+var ErrIDVersionNotSupported = errors.New("ID version is not supported")
+
+// String returns string representation of ID version
+func (v IDVersion) String() string {
+	return fmt.Sprintf("v%d", uint8(v))
+}
+
+// hasIDVersion2Flag returns true if the DID method byte of the type has the
+// version 2 flag. IDs of such types may have version 1 or version 2
+// checksum, IDs created before version 2 keep their version 1 checksum. The
+// method byte of unsupported DIDs (0b11111111) has no flag.
+func (t DIDType) hasIDVersion2Flag() bool {
+	return t[0]&idVersion2Flag != 0 && t[0] != 0b1111_1111
+}
+
+// WithIDVersion returns the same DID type with the version 2 flag set or
+// cleared. The flag does not select the checksum by itself, use
+// NewIDWithVersion to create IDs of version 2.
+func (t DIDType) WithIDVersion(v IDVersion) (DIDType, error) {
+	switch v {
+	case IDVersion1:
+		return DIDType{t[0] &^ idVersion2Flag, t[1]}, nil
+	case IDVersion2:
+		t2 := DIDType{t[0] | idVersion2Flag, t[1]}
+		if !t2.hasIDVersion2Flag() {
+			return DIDType{}, fmt.Errorf("%w: %v for DID type %v",
+				ErrIDVersionNotSupported, v, t)
+		}
+		return t2, nil
+	default:
+		return DIDType{}, fmt.Errorf("%w: %v", ErrIDVersionNotSupported, v)
+	}
+}
+
+// NewIDWithVersion creates a new ID of the version from a type and genesis.
+// Version 1 IDs are the same as created by NewID. Version 2 IDs have the
+// version 2 flag set in the type and SHA-256 based checksum.
+func NewIDWithVersion(typ DIDType, genesis [genesisLn]byte,
+	v IDVersion) (ID, error) {
+
+	switch v {
+	case IDVersion1:
+		return NewID(typ, genesis), nil
+	case IDVersion2:
+		typ2, err := typ.WithIDVersion(v)
+		if err != nil {
+			return ID{}, err
+		}
+		return newID(typ2, genesis, CalculateChecksumV2(typ2, genesis)), nil
+	default:
+		return ID{}, fmt.Errorf("%w: %v", ErrIDVersionNotSupported, v)
+	}
+}
+
+// IDVersionFromID returns the version of ID encoding. The version is
+// selected by the checksum: IDs with valid version 1 checksum are version 1
+// IDs of any type, version 2 checksum is valid only for types with version 2
+// flag. Returns error if ID checksum is not valid for any version.
+func IDVersionFromID(id ID) (IDVersion, error) {
+	v, ok := idVersion(id)
+	if !ok {
+		return 0, fmt.Errorf("%w: invalid checksum", ErrUnsupportedID)
+	}
+	return v, nil
+}
+
+// idVersion returns the version of ID by its checksum or false if checksum
+// is not valid
+func idVersion(id ID) (IDVersion, bool) {
+	typ, genesis, checksum, err := DecomposeID(id)
+	if err != nil || checksum == [2]byte{} {
+		return 0, false
+	}
+	if CalculateChecksum(typ, genesis) == checksum {
+		return IDVersion1, true
+	}
+	if typ.hasIDVersion2Flag() && CalculateChecksumV2(typ, genesis) == checksum {
+		return IDVersion2, true
+	}
+	return 0, false
+}
+
+// CalculateChecksumV2 returns the checksum of version 2 IDs for a given type
+// and genesis_root, where checksum is the first non-zero pair of bytes of
+//
+//	sha256( [type | root_genesis ] )
+func CalculateChecksumV2(typ DIDType, genesis [genesisLn]byte) [2]byte {
+	var toChecksum [29]byte
+	copy(toChecksum[:], typ[:])
+	copy(toChecksum[2:], genesis[:])
+
+	h := sha256.Sum256(toChecksum[:])
+	var checksum [2]byte
+	for i := 0; i < len(h); i += len(checksum) {
+		copy(checksum[:], h[i:])
+		if checksum != [2]byte{} {
+			break
+		}
+	}
+	return checksum
+}
+
+func findDIDMethodByExactValue(b byte) (DIDMethod, bool) {
+	for k, v := range DIDMethodByte {
+		if v == b {
+			return k, true
+		}
+	}
+	return DIDMethodOther, false
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDVersion2(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, Polygon, Amoy)
+	require.NoError(t, err)
+
+	typ2, err := typ.WithIDVersion(IDVersion2)
+	require.NoError(t, err)
+	require.Equal(t, DIDType{0b1000_0001, 0b0001_0011}, typ2)
+	require.Equal(t, DIDMethodIden3, typ2.Method())
+	require.Equal(t, Polygon, typ2.Blockchain())
+	require.Equal(t, Amoy, typ2.Network())
+	require.Equal(t, "iden3:polygon:amoy/v2", typ2.String())
+
+	typ1, err := typ2.WithIDVersion(IDVersion1)
+	require.NoError(t, err)
+	require.Equal(t, typ, typ1)
+
+	parsedTyp, err := ParseDIDType("iden3:polygon:amoy/v2")
+	require.NoError(t, err)
+	require.Equal(t, typ2, parsedTyp)
+	_, err = ParseDIDType("iden3:polygon:amoy/v3")
+	require.ErrorIs(t, err, ErrIDVersionNotSupported)
+
+	var genesis [genesisLn]byte
+	genesis32bytes := hashBytes([]byte("genesistest"))
+	copy(genesis[:], genesis32bytes[:])
+
+	id, err := NewIDWithVersion(typ, genesis, IDVersion2)
+	require.NoError(t, err)
+	require.Equal(t, typ2, id.Type())
+	require.True(t, CheckChecksum(id))
+	checksum := CalculateChecksumV2(typ2, genesis)
+	require.Equal(t, checksum[:], id[29:])
+	legacyChecksum := CalculateChecksum(typ2, genesis)
+	require.NotEqual(t, legacyChecksum[:], id[29:])
+
+	version, err := IDVersionFromID(id)
+	require.NoError(t, err)
+	require.Equal(t, IDVersion2, version)
+
+	id2, err := IDFromString(id.String())
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+
+	did, err := ParseDIDFromID(id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"polygon", "amoy"}, did.IDStrings[:2])
+	id3, err := IDFromDID(*did)
+	require.NoError(t, err)
+	require.Equal(t, id, id3)
+
+	// profiles keep the version of the identity
+	profile, err := ProfileID(id, big.NewInt(42))
+	require.NoError(t, err)
+	require.Equal(t, typ2, profile.Type())
+	require.True(t, CheckChecksum(profile))
+	version, err = IDVersionFromID(profile)
+	require.NoError(t, err)
+	require.Equal(t, IDVersion2, version)
+
+	// the flag alone does not select the checksum
+	idV1 := NewID(typ2, genesis)
+	legacyChecksum = CalculateChecksum(typ2, genesis)
+	require.Equal(t, legacyChecksum[:], idV1[29:])
+	version, err = IDVersionFromID(idV1)
+	require.NoError(t, err)
+	require.Equal(t, IDVersion1, version)
+}
+
+func TestIDVersion2_DetectsPermutation(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, Polygon, Amoy)
+	require.NoError(t, err)
+	typ2, err := typ.WithIDVersion(IDVersion2)
+	require.NoError(t, err)
+
+	var genesis [genesisLn]byte
+	genesis32bytes := hashBytes([]byte("genesistest"))
+	copy(genesis[:], genesis32bytes[:])
+
+	idV1 := NewID(typ, genesis)
+	idV2, err := NewIDWithVersion(typ, genesis, IDVersion2)
+	require.NoError(t, err)
+	require.Equal(t, typ2, idV2.Type())
+
+	// swap two genesis bytes
+	idV1[5], idV1[6] = idV1[6], idV1[5]
+	idV2[5], idV2[6] = idV2[6], idV2[5]
+
+	require.True(t, CheckChecksum(idV1))
+	require.False(t, CheckChecksum(idV2))
+
+	_, err = IDVersionFromID(idV2)
+	require.ErrorIs(t, err, ErrUnsupportedID)
+}
+
+func TestIDVersion_Legacy(t *testing.T) {
+	id, err := IDFromString("wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+	version, err := IDVersionFromID(id)
+	require.NoError(t, err)
+	require.Equal(t, IDVersion1, version)
+	require.Equal(t, "v1", version.String())
+
+	_, err = DIDType{0xff, 0xff}.WithIDVersion(IDVersion2)
+	require.ErrorIs(t, err, ErrIDVersionNotSupported)
+}
+
+func TestIDVersion_HighBitLegacyID(t *testing.T) {
+	// version 1 ID of a method with high bit of the method byte created
+	// before version 2 IDs were introduced
+	err := RegisterDIDMethodNetwork(DIDMethodNetworkParams{
+		Method:      "highbit",
+		Blockchain:  "highbitchain",
+		Network:     "highbitnet",
+		NetworkFlag: 0b0000_0001,
+	}, WithDIDMethodByte(0b1001_0000))
+	require.NoError(t, err)
+	typ, err := BuildDIDType("highbit", "highbitchain", "highbitnet")
+	require.NoError(t, err)
+
+	var genesis [genesisLn]byte
+	genesis32bytes := hashBytes([]byte("genesistest"))
+	copy(genesis[:], genesis32bytes[:])
+	id := NewID(typ, genesis)
+	legacyChecksum := CalculateChecksum(typ, genesis)
+	require.Equal(t, legacyChecksum[:], id[29:])
+
+	checkID := func() {
+		t.Helper()
+		require.True(t, CheckChecksum(id))
+		id2, err := IDFromString(id.String())
+		require.NoError(t, err)
+		require.Equal(t, id, id2)
+		version, err := IDVersionFromID(id)
+		require.NoError(t, err)
+		require.Equal(t, IDVersion1, version)
+	}
+	checkID()
+
+	// registering the method byte without the high bit does not change
+	// validation of existing IDs
+	err = RegisterDIDMethod("lowbit", 0b0001_0000)
+	require.NoError(t, err)
+	checkID()
+	require.Equal(t, id, NewID(typ, genesis))
+}
+
+func TestPublishReadOnlyID_IDVersion2(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, ReadOnly, NoNetwork)
+	require.NoError(t, err)
+	idV1, err := NewIDFromIdenState(typ, big.NewInt(1))
+	require.NoError(t, err)
+	_, genesis, _, err := DecomposeID(*idV1)
+	require.NoError(t, err)
+
+	id, err := NewIDWithVersion(typ, genesis, IDVersion2)
+	require.NoError(t, err)
+	require.True(t, IsReadOnlyID(id))
+
+	published, err := PublishReadOnlyID(id, Polygon, Amoy)
+	require.NoError(t, err)
+	version, err := IDVersionFromID(published)
+	require.NoError(t, err)
+	require.Equal(t, IDVersion2, version)
+	require.True(t, published.Type().hasIDVersion2Flag())
+}
@@ -0,0 +1,124 @@
+// Package sqltest provides in-memory database/sql driver to test
+// implementations of sql.Scanner and driver.Valuer
+package sqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// DriverName is the name of the driver registered in database/sql
+const DriverName = "iden3-sqltest"
+
+var registerOnce sync.Once
+
+// Open opens in-memory database by name. Databases with the same name share
+// the data. Every executed statement appends its arguments as a row, every
+// query returns all appended rows. SQL text of statements is ignored.
+func Open(name string) (*sql.DB, error) {
+	registerOnce.Do(func() {
+		sql.Register(DriverName, &memDriver{tables: make(map[string]*table)})
+	})
+	return sql.Open(DriverName, name)
+}
+
+type memDriver struct {
+	mu     sync.Mutex
+	tables map[string]*table
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t, ok := d.tables[name]
+	if !ok {
+		t = &table{}
+		d.tables[name] = t
+	}
+	return &conn{t: t}, nil
+}
+
+type table struct {
+	mu   sync.Mutex
+	rows [][]driver.Value
+}
+
+type conn struct {
+	t *table
+}
+
+func (c *conn) Prepare(string) (driver.Stmt, error) {
+	return &stmt{t: c.t}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type stmt struct {
+	t *table
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	row := make([]driver.Value, len(args))
+	for i, v := range args {
+		if b, ok := v.([]byte); ok {
+			v = append([]byte(nil), b...)
+		}
+		row[i] = v
+	}
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.t.rows = append(s.t.rows, row)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stmt) Query([]driver.Value) (driver.Rows, error) {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	return &rows{rows: append([][]driver.Value(nil), s.t.rows...)}, nil
+}
+
+type rows struct {
+	rows [][]driver.Value
+	next int
+}
+
+func (r *rows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	columns := make([]string, len(r.rows[0]))
+	for i := range columns {
+		columns[i] = string(rune('a' + i))
+	}
+	return columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next == len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+// ProfileDID calculates the Profile DID from the Identity DID and profile
+// nonce. Method, blockchain and network of the Identity DID are preserved.
+// If nonce is empty or zero, DID of the Identity is returned.
+func ProfileDID(did w3c.DID, nonce *big.Int) (*w3c.DID, error) {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return nil, err
+	}
+
+	profileID, err := ProfileID(id, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDIDFromID(profileID)
+}
+
+// ProfileNonceFromDomain derives deterministic profile nonce from the domain
+// string (e.g. verifier domain). The nonce is a Poseidon hash of the domain
+// bytes, so it is always in the field.
+func ProfileNonceFromDomain(domain string) (*big.Int, error) {
+	if domain == "" {
+		return nil, errors.New("domain is empty")
+	}
+
+	nonce, err := poseidon.HashBytes([]byte(domain))
+	if err != nil {
+		return nil, err
+	}
+
+	if nonce.Sign() == 0 {
+		return nil, errors.New("domain hashes to zero nonce")
+	}
+
+	return nonce, nil
+}
+
+type profileEntry struct {
+	identity ID
+	nonce    *big.Int
+}
+
+// ProfileSet records profile nonces of identities and answers if DID is a
+// profile of the identity. Zero value is not usable, use NewProfileSet.
+// ProfileSet is not safe for concurrent use.
+type ProfileSet struct {
+	nonces   map[ID][]*big.Int
+	profiles map[ID]profileEntry
+}
+
+// NewProfileSet creates new empty ProfileSet
+func NewProfileSet() *ProfileSet {
+	return &ProfileSet{
+		nonces:   make(map[ID][]*big.Int),
+		profiles: make(map[ID]profileEntry),
+	}
+}
+
+// Add records profile nonce of the identity and returns the Profile DID.
+// Adding the same nonce twice is not an error.
+func (s *ProfileSet) Add(identity w3c.DID, nonce *big.Int) (*w3c.DID, error) {
+	if nonce == nil || nonce.Sign() == 0 {
+		return nil, errors.New("profile nonce is empty")
+	}
+
+	id, err := IDFromDID(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	profileID, err := ProfileID(id, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	profileDID, err := ParseDIDFromID(profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.profiles[profileID]; !ok {
+		n := new(big.Int).Set(nonce)
+		s.nonces[id] = append(s.nonces[id], n)
+		s.profiles[profileID] = profileEntry{identity: id, nonce: n}
+	}
+
+	return profileDID, nil
+}
+
+// Nonces returns recorded profile nonces of the identity in order they were
+// added.
+func (s *ProfileSet) Nonces(identity w3c.DID) ([]*big.Int, error) {
+	id, err := IDFromDID(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	nonces := make([]*big.Int, len(s.nonces[id]))
+	for i, n := range s.nonces[id] {
+		nonces[i] = new(big.Int).Set(n)
+	}
+	return nonces, nil
+}
+
+// IsProfileOf checks if profile DID was derived from identity DID with one of
+// the recorded nonces and returns that nonce. Identity is considered to be
+// its own profile with zero nonce.
+func (s *ProfileSet) IsProfileOf(profile,
+	identity w3c.DID) (bool, *big.Int, error) {
+
+	profileID, err := IDFromDID(profile)
+	if err != nil {
+		return false, nil, err
+	}
+
+	id, err := IDFromDID(identity)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if profileID == id {
+		return true, big.NewInt(0), nil
+	}
+
+	e, ok := s.profiles[profileID]
+	if !ok || e.identity != id {
+		return false, nil, nil
+	}
+
+	return true, new(big.Int).Set(e.nonce), nil
+}
+
+// IdentityOf returns the identity DID and nonce the profile DID was derived
+// from. Returns false if profile is unknown to the set.
+func (s *ProfileSet) IdentityOf(profile w3c.DID) (*w3c.DID, *big.Int, bool,
+	error) {
+
+	profileID, err := IDFromDID(profile)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	e, ok := s.profiles[profileID]
+	if !ok {
+		return nil, nil, false, nil
+	}
+
+	identity, err := ParseDIDFromID(e.identity)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return identity, new(big.Int).Set(e.nonce), true, nil
+}
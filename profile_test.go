@@ -0,0 +1,97 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileDID(t *testing.T) {
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:mumbai:wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+
+	profile, err := ProfileDID(*did, big.NewInt(10))
+	require.NoError(t, err)
+	require.Equal(t, "iden3", profile.Method)
+	require.Equal(t, []string{"polygon", "mumbai"}, profile.IDStrings[:2])
+
+	id, err := IDFromDID(*did)
+	require.NoError(t, err)
+	profileID, err := ProfileID(id, big.NewInt(10))
+	require.NoError(t, err)
+	require.Equal(t, profileID.String(), profile.IDStrings[2])
+
+	same, err := ProfileDID(*did, nil)
+	require.NoError(t, err)
+	require.Equal(t, did.String(), same.String())
+}
+
+func TestProfileNonceFromDomain(t *testing.T) {
+	n1, err := ProfileNonceFromDomain("verifier.example.com")
+	require.NoError(t, err)
+	n2, err := ProfileNonceFromDomain("verifier.example.com")
+	require.NoError(t, err)
+	require.Equal(t, n1, n2)
+	require.NotEqual(t, 0, n1.Sign())
+
+	n3, err := ProfileNonceFromDomain("other.example.com")
+	require.NoError(t, err)
+	require.NotEqual(t, n1, n3)
+
+	_, err = ProfileNonceFromDomain("")
+	require.EqualError(t, err, "domain is empty")
+}
+
+func TestProfileSet(t *testing.T) {
+	identity, err := w3c.ParseDID(
+		"did:iden3:polygon:mumbai:wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+	other := helperBuildDIDFromType(t, DIDMethodIden3, Polygon, Mumbai)
+
+	s := NewProfileSet()
+	p1, err := s.Add(*identity, big.NewInt(1))
+	require.NoError(t, err)
+	p2, err := s.Add(*identity, big.NewInt(2))
+	require.NoError(t, err)
+	_, err = s.Add(*identity, big.NewInt(2))
+	require.NoError(t, err)
+	_, err = s.Add(*identity, nil)
+	require.EqualError(t, err, "profile nonce is empty")
+
+	nonces, err := s.Nonces(*identity)
+	require.NoError(t, err)
+	require.Equal(t, []*big.Int{big.NewInt(1), big.NewInt(2)}, nonces)
+
+	ok, nonce, err := s.IsProfileOf(*p2, *identity)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(2), nonce)
+
+	ok, nonce, err = s.IsProfileOf(*identity, *identity)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(0), nonce)
+
+	ok, _, err = s.IsProfileOf(*p1, *other)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	unknown, err := ProfileDID(*identity, big.NewInt(3))
+	require.NoError(t, err)
+	ok, _, err = s.IsProfileOf(*unknown, *identity)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	gotIdentity, nonce, ok, err := s.IdentityOf(*p1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, identity.String(), gotIdentity.String())
+	require.Equal(t, big.NewInt(1), nonce)
+
+	_, _, ok, err = s.IdentityOf(*unknown)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
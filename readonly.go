@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// ErrReadOnlyIdentity returned when operation requires identity bound to
+// blockchain, but readonly identity is given.
+var ErrReadOnlyIdentity = errors.New("readonly identity")
+
+// NewReadOnlyID creates readonly ID of the DID method from genesis
+func NewReadOnlyID(method DIDMethod, genesis [genesisLn]byte) (ID, error) {
+	typ, err := BuildDIDType(method, ReadOnly, NoNetwork)
+	if err != nil {
+		return ID{}, err
+	}
+	return NewID(typ, genesis), nil
+}
+
+// NewReadOnlyDID creates readonly DID of the DID method from genesis
+func NewReadOnlyDID(method DIDMethod,
+	genesis [genesisLn]byte) (*w3c.DID, error) {
+
+	id, err := NewReadOnlyID(method, genesis)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDIDFromID(id)
+}
+
+// IsReadOnlyID returns true if ID is a readonly identity
+func IsReadOnlyID(id ID) bool {
+	return id.Type().IsReadOnly()
+}
+
+// IsReadOnlyDID returns true if DID is a readonly identity
+func IsReadOnlyDID(did w3c.DID) bool {
+	id, err := IDFromDID(did)
+	if err != nil {
+		return false
+	}
+	return IsReadOnlyID(id)
+}
+
+// PublishReadOnlyID converts readonly ID into the ID bound to blockchain and
+// network. The method, ID version and the genesis of the ID are preserved,
+// only the type bytes and the checksum change.
+func PublishReadOnlyID(id ID, blockchain Blockchain,
+	network NetworkID) (ID, error) {
+
+	if !IsReadOnlyID(id) {
+		return ID{}, fmt.Errorf("%w: ID %v is not readonly", ErrUnsupportedID,
+			id.String())
+	}
+
+	if blockchain == ReadOnly {
+		return ID{}, fmt.Errorf("%w: can't publish to readonly blockchain",
+			ErrBlockchainNotSupportedForDID)
+	}
+
+	typ, err := BuildDIDType(id.Type().Method(), blockchain, network)
+	if err != nil {
+		return ID{}, err
+	}
+	version, err := IDVersionFromID(id)
+	if err != nil {
+		return ID{}, err
+	}
+
+	_, genesis, _, err := DecomposeID(id)
+	if err != nil {
+		return ID{}, err
+	}
+
+	return NewIDWithVersion(typ, genesis, version)
+}
+
+// PublishReadOnlyDID converts readonly DID into the DID bound to blockchain
+// and network.
+func PublishReadOnlyDID(did w3c.DID, blockchain Blockchain,
+	network NetworkID) (*w3c.DID, error) {
+
+	id, err := IDFromDID(did)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err = PublishReadOnlyID(id, blockchain, network)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDIDFromID(id)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReadOnlyID(t *testing.T) {
+	typ, err := BuildDIDType(DIDMethodIden3, ReadOnly, NoNetwork)
+	require.NoError(t, err)
+	stateID, err := NewIDFromIdenState(typ, big.NewInt(1))
+	require.NoError(t, err)
+	_, genesis, _, err := DecomposeID(*stateID)
+	require.NoError(t, err)
+
+	id, err := NewReadOnlyID(DIDMethodIden3, genesis)
+	require.NoError(t, err)
+	require.Equal(t, *stateID, id)
+	require.True(t, IsReadOnlyID(id))
+
+	did, err := NewReadOnlyDID(DIDMethodIden3, genesis)
+	require.NoError(t, err)
+	require.Equal(t,
+		"did:iden3:readonly:tJ93RwaVfE1PEMxd5rpZZuPtLCwbEaDCrNBhAy8HM",
+		did.String())
+	require.True(t, IsReadOnlyDID(*did))
+
+	_, err = NewReadOnlyID("unknown", genesis)
+	require.ErrorIs(t, err, ErrDIDMethodNotSupported)
+}
+
+func TestIsReadOnlyDID(t *testing.T) {
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:mumbai:wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ")
+	require.NoError(t, err)
+	require.False(t, IsReadOnlyDID(*did))
+
+	did, err = w3c.ParseDID("did:something:x")
+	require.NoError(t, err)
+	require.False(t, IsReadOnlyDID(*did))
+}
+
+func TestPublishReadOnlyDID(t *testing.T) {
+	did, err := w3c.ParseDID(
+		"did:iden3:readonly:tJ93RwaVfE1PEMxd5rpZZuPtLCwbEaDCrNBhAy8HM")
+	require.NoError(t, err)
+
+	published, err := PublishReadOnlyDID(*did, Polygon, Amoy)
+	require.NoError(t, err)
+	want := helperBuildDIDFromType(t, DIDMethodIden3, Polygon, Amoy)
+	require.Equal(t, want.String(), published.String())
+	require.False(t, IsReadOnlyDID(*published))
+
+	_, err = PublishReadOnlyDID(*published, Polygon, Main)
+	require.ErrorIs(t, err, ErrUnsupportedID)
+
+	_, err = PublishReadOnlyDID(*did, ReadOnly, NoNetwork)
+	require.ErrorIs(t, err, ErrBlockchainNotSupportedForDID)
+
+	_, err = PublishReadOnlyDID(*did, Polygon, Sepolia)
+	require.ErrorIs(t, err, ErrNetworkNotSupportedForDID)
+}
+
+func TestChainIDfromID_ReadOnly(t *testing.T) {
+	did, err := w3c.ParseDID(
+		"did:iden3:readonly:tJ93RwaVfE1PEMxd5rpZZuPtLCwbEaDCrNBhAy8HM")
+	require.NoError(t, err)
+
+	_, err = ChainIDfromDID(*did)
+	require.ErrorIs(t, err, ErrReadOnlyIdentity)
+
+	_, err = CAIP2ChainIDFromDID(*did)
+	require.ErrorIs(t, err, ErrReadOnlyIdentity)
+}
@@ -0,0 +1,308 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// Fragments of verification methods of DID documents built by Iden3Resolver
+const (
+	StateInfoVerificationMethodFragment       = "state-info"
+	EthereumBasedIDVerificationMethodFragment = "ethereum-based-id"
+)
+
+// Iden3Resolver is w3c.Resolver of DIDs of iden3 based methods, i.e. methods
+// registered in DIDMethodByte. It builds DID document from the ID and the
+// identity state from StateSource:
+//   - Iden3StateInfo2023 verification method with identity state and GIST
+//     root, for identities bound to a chain;
+//   - EcdsaSecp256k1RecoveryMethod2020 verification method with the CAIP-10
+//     account of identity controlled by Ethereum address.
+//
+// The latest state is resolved by default. Historical state is selected by
+// StateQuery passed in resolution options (see StateQuery.ResolutionOptions).
+// VersionID of resolution options and of document metadata is the state hex
+// as returned by State.Hex.
+type Iden3Resolver struct {
+	source         StateSource
+	stateContracts map[ChainID][ethAddressLn]byte
+}
+
+// NewIden3Resolver creates new Iden3Resolver. stateContracts are addresses of
+// state contracts by chain ID.
+func NewIden3Resolver(source StateSource,
+	stateContracts map[ChainID][ethAddressLn]byte) *Iden3Resolver {
+
+	contracts := make(map[ChainID][ethAddressLn]byte, len(stateContracts))
+	for k, v := range stateContracts {
+		contracts[k] = v
+	}
+	return &Iden3Resolver{source: source, stateContracts: contracts}
+}
+
+// Resolve resolves DID to DID document
+func (r *Iden3Resolver) Resolve(ctx context.Context, did w3c.DID,
+	opts w3c.ResolutionOptions) (w3c.ResolutionResult, error) {
+
+	if did.IsURL() {
+		return w3c.NewResolutionErrorResult(w3c.ErrorCodeInvalidDID,
+			fmt.Errorf("not a DID: %q", did.String()))
+	}
+
+	contentType := opts.Accept
+	switch contentType {
+	case "":
+		contentType = w3c.ContentTypeDIDLDJSON
+	case w3c.ContentTypeDIDJSON, w3c.ContentTypeDIDLDJSON:
+	default:
+		return w3c.NewResolutionErrorResult(
+			w3c.ErrorCodeRepresentationNotSupported,
+			fmt.Errorf("unsupported representation: %q", opts.Accept))
+	}
+
+	id, err := idFromDID(did)
+	if errors.Is(err, ErrMethodUnknown) {
+		return w3c.NewResolutionErrorResult(w3c.ErrorCodeMethodNotSupported,
+			fmt.Errorf("%w: %v", err, did.Method))
+	} else if err != nil {
+		return w3c.NewResolutionErrorResult(w3c.ErrorCodeInvalidDID, err)
+	}
+
+	q, err := stateQueryFromOptions(opts)
+	if err != nil {
+		return w3c.NewResolutionErrorResult(w3c.ErrorCodeInvalidOptions, err)
+	}
+
+	doc := &w3c.DIDDocument{
+		ID: w3c.DID{Method: did.Method, ID: did.ID, IDStrings: did.IDStrings},
+	}
+	var docMeta w3c.DIDDocumentMetadata
+
+	chainID, err := ChainIDfromID(id)
+	boundToChain := err == nil
+	switch {
+	case errors.Is(err, ErrReadOnlyIdentity):
+		// identity is not bound to any chain, so it has no state
+	case err != nil:
+		return w3c.NewResolutionErrorResult(w3c.ErrorCodeInternalError, err)
+	default:
+		vm, meta, err := r.stateVerificationMethod(ctx, doc.ID, chainID, id,
+			q)
+		if err != nil {
+			return resolutionErrorResult(err)
+		}
+		docMeta = meta
+		doc.VerificationMethod = append(doc.VerificationMethod, vm)
+	}
+
+	if IsEthereumControlled(id) && boundToChain {
+		account, err := CAIP10AccountIDFromID(id)
+		if err != nil {
+			return w3c.NewResolutionErrorResult(w3c.ErrorCodeInternalError,
+				err)
+		}
+		doc.VerificationMethod = append(doc.VerificationMethod,
+			w3c.VerificationMethod{
+				ID: doc.ID.String() + "#" +
+					EthereumBasedIDVerificationMethodFragment,
+				Type:                w3c.EcdsaSecp256k1RecoveryMethod2020Type,
+				Controller:          doc.ID.String(),
+				BlockchainAccountID: account.String(),
+			})
+	}
+
+	for _, vm := range doc.VerificationMethod {
+		doc.Authentication = append(doc.Authentication,
+			w3c.VerificationRelationship{Reference: vm.ID})
+	}
+
+	if contentType == w3c.ContentTypeDIDLDJSON {
+		doc.Context = doc.JSONLDContext()
+	}
+
+	return w3c.ResolutionResult{
+		DIDResolutionMetadata: w3c.DIDResolutionMetadata{
+			ContentType: contentType,
+		},
+		DIDDocument:         doc,
+		DIDDocumentMetadata: docMeta,
+	}, nil
+}
+
+func (r *Iden3Resolver) stateVerificationMethod(ctx context.Context,
+	did w3c.DID, chainID ChainID, id ID, q StateQuery) (w3c.VerificationMethod,
+	w3c.DIDDocumentMetadata, error) {
+
+	contract, ok := r.stateContracts[chainID]
+	if !ok {
+		return w3c.VerificationMethod{}, w3c.DIDDocumentMetadata{},
+			fmt.Errorf("state contract for chain %v is not configured",
+				chainID)
+	}
+	contractAccount := CAIP10AccountID{
+		ChainID: CAIP2ChainIDFromChainID(chainID),
+		Address: EthAddressToChecksumHex(contract),
+	}
+
+	vm := w3c.VerificationMethod{
+		ID:                   did.String() + "#" + StateInfoVerificationMethodFragment,
+		Type:                 w3c.Iden3StateInfo2023Type,
+		Controller:           did.String(),
+		StateContractAddress: contractAccount.String(),
+	}
+	var meta w3c.DIDDocumentMetadata
+
+	info, err := r.identityState(ctx, chainID, id, q)
+	if err != nil {
+		return w3c.VerificationMethod{}, w3c.DIDDocumentMetadata{}, err
+	}
+	published := info != nil
+	vm.Published = &published
+	if info != nil {
+		vm.Info = toW3CStateInfo(info)
+		meta.Updated = w3c.FormatTime(info.CreatedAt)
+		meta.VersionID = info.State.Hex()
+		if !info.ReplacedAt.IsZero() {
+			meta.NextUpdate = w3c.FormatTime(info.ReplacedAt)
+			meta.NextVersionID = info.ReplacedByState.Hex()
+		}
+	} else if q.State != nil {
+		meta.VersionID = q.State.Hex()
+	}
+
+	gist, err := r.gistRoot(ctx, chainID, q)
+	if err != nil {
+		return w3c.VerificationMethod{}, w3c.DIDDocumentMetadata{}, err
+	}
+	if gist != nil {
+		vm.Global = toW3CGistInfo(gist)
+	}
+
+	return vm, meta, nil
+}
+
+// identityState returns the state of identity selected by the query or nil
+// if identity is in unpublished genesis state
+func (r *Iden3Resolver) identityState(ctx context.Context, chainID ChainID,
+	id ID, q StateQuery) (*IdentityStateInfo, error) {
+
+	var (
+		info *IdentityStateInfo
+		err  error
+	)
+	switch {
+	case q.State != nil:
+		info, err = r.source.StateInfo(ctx, chainID, id, *q.State)
+		if errors.Is(err, ErrStateNotFound) {
+			genesisID, err2 := genesisIDOf(id, q.State.BigInt())
+			if err2 == nil && genesisID == id {
+				return nil, nil
+			}
+			return nil, &w3c.ResolutionError{Code: w3c.ErrorCodeNotFound,
+				Err: err}
+		}
+	case !q.VersionTime.IsZero():
+		info, err = r.source.StateAt(ctx, chainID, id, q.VersionTime)
+	default:
+		info, err = r.source.LatestState(ctx, chainID, id)
+	}
+	if errors.Is(err, ErrStateNotFound) {
+		return nil, nil
+	}
+	return info, err
+}
+
+// gistRoot returns GIST root selected by the query or nil if there is no
+// GIST root
+func (r *Iden3Resolver) gistRoot(ctx context.Context, chainID ChainID,
+	q StateQuery) (*GistRootInfo, error) {
+
+	var (
+		info *GistRootInfo
+		err  error
+	)
+	switch {
+	case q.GistRoot != nil:
+		info, err = r.source.GistRootInfo(ctx, chainID, *q.GistRoot)
+		if errors.Is(err, ErrGistRootNotFound) {
+			return nil, &w3c.ResolutionError{Code: w3c.ErrorCodeNotFound,
+				Err: err}
+		}
+	case !q.VersionTime.IsZero():
+		info, err = r.source.GistRootAt(ctx, chainID, q.VersionTime)
+	default:
+		info, err = r.source.LatestGistRoot(ctx, chainID)
+	}
+	if errors.Is(err, ErrGistRootNotFound) {
+		return nil, nil
+	}
+	return info, err
+}
+
+// stateQueryFromOptions returns the state query from resolution options.
+// VersionID is the state hex and must match the state of MethodOptions if
+// both are set.
+func stateQueryFromOptions(opts w3c.ResolutionOptions) (StateQuery, error) {
+	q, err := StateQueryFromResolutionOptions(opts)
+	if err != nil || opts.VersionID == "" {
+		return q, err
+	}
+
+	s, err := parseStateParam("versionId", opts.VersionID)
+	if err != nil {
+		return StateQuery{}, err
+	}
+	if q.State != nil && *q.State != *s {
+		return StateQuery{}, fmt.Errorf(
+			"%w: versionId and state select different states",
+			ErrInvalidStateQuery)
+	}
+	q.State = s
+	return q, nil
+}
+
+// resolutionErrorResult returns the result of resolution error. Errors other
+// than *w3c.ResolutionError are internal errors.
+func resolutionErrorResult(err error) (w3c.ResolutionResult, error) {
+	var resErr *w3c.ResolutionError
+	if errors.As(err, &resErr) {
+		return w3c.NewResolutionErrorResult(resErr.Code, resErr.Err)
+	}
+	return w3c.NewResolutionErrorResult(w3c.ErrorCodeInternalError, err)
+}
+
+func toW3CStateInfo(info *IdentityStateInfo) *w3c.StateInfo {
+	return &w3c.StateInfo{
+		ID:                  info.ID.String(),
+		State:               info.State.String(),
+		ReplacedByState:     info.ReplacedByState.String(),
+		CreatedAtTimestamp:  formatUnixTime(info.CreatedAt),
+		ReplacedAtTimestamp: formatUnixTime(info.ReplacedAt),
+		CreatedAtBlock:      strconv.FormatUint(info.CreatedAtBlock, 10),
+		ReplacedAtBlock:     strconv.FormatUint(info.ReplacedAtBlock, 10),
+	}
+}
+
+func toW3CGistInfo(info *GistRootInfo) *w3c.GistInfo {
+	return &w3c.GistInfo{
+		Root:                info.Root.String(),
+		ReplacedByRoot:      info.ReplacedByRoot.String(),
+		CreatedAtTimestamp:  formatUnixTime(info.CreatedAt),
+		ReplacedAtTimestamp: formatUnixTime(info.ReplacedAt),
+		CreatedAtBlock:      strconv.FormatUint(info.CreatedAtBlock, 10),
+		ReplacedAtBlock:     strconv.FormatUint(info.ReplacedAtBlock, 10),
+	}
+}
+
+// formatUnixTime returns decimal unix timestamp or 0 for zero time
+func formatUnixTime(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,354 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+var testStateContract = ethAddrFromHex(
+	"1a4cc30f2aa0377b0c3bc9848766d90cb4404124")
+
+func helperNewResolver(t testing.TB) (*Iden3Resolver, *InMemoryStateSource) {
+	t.Helper()
+	source := NewInMemoryStateSource()
+	r := NewIden3Resolver(source,
+		map[ChainID][ethAddressLn]byte{80002: testStateContract})
+	return r, source
+}
+
+func TestIden3Resolver_Resolve(t *testing.T) {
+	r, source := helperNewResolver(t)
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+	id, err := IDFromDID(*did)
+	require.NoError(t, err)
+
+	// not published yet
+	res, err := r.Resolve(context.Background(), *did, w3c.ResolutionOptions{})
+	require.NoError(t, err)
+	require.Equal(t, w3c.ContentTypeDIDLDJSON,
+		res.DIDResolutionMetadata.ContentType)
+	require.NoError(t, res.DIDDocument.Validate())
+	require.Len(t, res.DIDDocument.VerificationMethod, 1)
+	vm := res.DIDDocument.VerificationMethod[0]
+	require.Equal(t, did.String()+"#state-info", vm.ID)
+	require.Equal(t, w3c.Iden3StateInfo2023Type, vm.Type)
+	require.Equal(t, "eip155:80002:0x1a4cC30f2aA0377b0c3bc9848766D90cb4404124",
+		vm.StateContractAddress)
+	require.False(t, *vm.Published)
+	require.Nil(t, vm.Info)
+	require.Nil(t, vm.Global)
+	require.Equal(t, []interface{}{w3c.DIDContextV1,
+		w3c.Iden3StateInfo2023Context}, res.DIDDocument.Context)
+
+	state1, err := NewStateFromBigInt(big.NewInt(1))
+	require.NoError(t, err)
+	state2, err := NewStateFromBigInt(big.NewInt(2))
+	require.NoError(t, err)
+	root, err := NewStateFromBigInt(big.NewInt(100))
+	require.NoError(t, err)
+	t1 := time.Unix(1700000000, 0)
+	t2 := time.Unix(1700000100, 0)
+	require.NoError(t, source.PublishState(80002, id, state1, t1, 10))
+	require.NoError(t, source.PublishState(80002, id, state2, t2, 20))
+	require.NoError(t, source.PublishGistRoot(80002, root, t2, 20))
+
+	res, err = r.Resolve(context.Background(), *did,
+		w3c.ResolutionOptions{Accept: w3c.ContentTypeDIDJSON})
+	require.NoError(t, err)
+	require.Equal(t, w3c.ContentTypeDIDJSON,
+		res.DIDResolutionMetadata.ContentType)
+	require.Nil(t, res.DIDDocument.Context)
+	require.Equal(t, "2023-11-14T22:15:00Z", res.DIDDocumentMetadata.Updated)
+	vm = res.DIDDocument.VerificationMethod[0]
+	require.True(t, *vm.Published)
+	require.Equal(t, &w3c.StateInfo{
+		ID:                  id.String(),
+		State:               "2",
+		ReplacedByState:     "0",
+		CreatedAtTimestamp:  "1700000100",
+		ReplacedAtTimestamp: "0",
+		CreatedAtBlock:      "20",
+		ReplacedAtBlock:     "0",
+	}, vm.Info)
+	require.Equal(t, "100", vm.Global.Root)
+	require.Equal(t, []w3c.VerificationRelationship{{Reference: vm.ID}},
+		res.DIDDocument.Authentication)
+
+	_, err = json.Marshal(res)
+	require.NoError(t, err)
+}
+
+func TestIden3Resolver_HistoricalState(t *testing.T) {
+	ctx := context.Background()
+	r, source := helperNewResolver(t)
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+	id, err := IDFromDID(*did)
+	require.NoError(t, err)
+
+	state1, err := NewStateFromBigInt(big.NewInt(1))
+	require.NoError(t, err)
+	state2, err := NewStateFromBigInt(big.NewInt(2))
+	require.NoError(t, err)
+	root1, err := NewStateFromBigInt(big.NewInt(100))
+	require.NoError(t, err)
+	root2, err := NewStateFromBigInt(big.NewInt(200))
+	require.NoError(t, err)
+	t1 := time.Unix(1700000000, 0)
+	t2 := time.Unix(1700000100, 0)
+	require.NoError(t, source.PublishState(80002, id, state1, t1, 10))
+	require.NoError(t, source.PublishState(80002, id, state2, t2, 20))
+	require.NoError(t, source.PublishGistRoot(80002, root1, t1, 10))
+	require.NoError(t, source.PublishGistRoot(80002, root2, t2, 20))
+
+	// by explicit state
+	res, err := r.Resolve(ctx, *did,
+		StateQuery{State: &state1, GistRoot: &root1}.ResolutionOptions())
+	require.NoError(t, err)
+	vm := res.DIDDocument.VerificationMethod[0]
+	require.Equal(t, "1", vm.Info.State)
+	require.Equal(t, "2", vm.Info.ReplacedByState)
+	require.Equal(t, "100", vm.Global.Root)
+	require.Equal(t, w3c.DIDDocumentMetadata{
+		Updated:       "2023-11-14T22:13:20Z",
+		NextUpdate:    "2023-11-14T22:15:00Z",
+		VersionID:     state1.Hex(),
+		NextVersionID: state2.Hex(),
+	}, res.DIDDocumentMetadata)
+
+	// by versionId
+	res, err = r.Resolve(ctx, *did,
+		w3c.ResolutionOptions{VersionID: state1.Hex()})
+	require.NoError(t, err)
+	require.Equal(t, "1",
+		res.DIDDocument.VerificationMethod[0].Info.State)
+	require.Equal(t, "200",
+		res.DIDDocument.VerificationMethod[0].Global.Root)
+
+	// by time
+	res, err = r.Resolve(ctx, *did,
+		StateQuery{VersionTime: t1.Add(time.Minute)}.ResolutionOptions())
+	require.NoError(t, err)
+	vm = res.DIDDocument.VerificationMethod[0]
+	require.Equal(t, "1", vm.Info.State)
+	require.Equal(t, "100", vm.Global.Root)
+
+	// before the first state identity is in genesis state
+	res, err = r.Resolve(ctx, *did,
+		StateQuery{VersionTime: t1.Add(-time.Minute)}.ResolutionOptions())
+	require.NoError(t, err)
+	vm = res.DIDDocument.VerificationMethod[0]
+	require.False(t, *vm.Published)
+	require.Nil(t, vm.Global)
+
+	// genesis state is not published but is a valid state of identity
+	genesis, err := StateFromTreeRoots(big.NewInt(1), big.NewInt(2),
+		big.NewInt(3))
+	require.NoError(t, err)
+	res, err = r.Resolve(ctx, *did,
+		StateQuery{State: &genesis}.ResolutionOptions())
+	require.NoError(t, err)
+	require.False(t, *res.DIDDocument.VerificationMethod[0].Published)
+	require.Equal(t, genesis.Hex(), res.DIDDocumentMetadata.VersionID)
+
+	state3, err := NewStateFromBigInt(big.NewInt(3))
+	require.NoError(t, err)
+	testCases := []struct {
+		name string
+		opts w3c.ResolutionOptions
+		code string
+	}{
+		{
+			name: "unknown state",
+			opts: StateQuery{State: &state3}.ResolutionOptions(),
+			code: w3c.ErrorCodeNotFound,
+		},
+		{
+			name: "unknown gist root",
+			opts: StateQuery{GistRoot: &state3}.ResolutionOptions(),
+			code: w3c.ErrorCodeNotFound,
+		},
+		{
+			name: "versionId conflicts with state",
+			opts: w3c.ResolutionOptions{
+				VersionID:     state2.Hex(),
+				MethodOptions: map[string]interface{}{"state": state1},
+			},
+			code: w3c.ErrorCodeInvalidOptions,
+		},
+		{
+			name: "invalid state option",
+			opts: w3c.ResolutionOptions{
+				MethodOptions: map[string]interface{}{"state": "xyz"},
+			},
+			code: w3c.ErrorCodeInvalidOptions,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := r.Resolve(ctx, *did, tc.opts)
+			var resErr *w3c.ResolutionError
+			require.True(t, errors.As(err, &resErr))
+			require.Equal(t, tc.code, resErr.Code)
+			require.Equal(t, tc.code, res.DIDResolutionMetadata.Error)
+		})
+	}
+}
+
+func TestIden3Resolver_Dereference(t *testing.T) {
+	r, source := helperNewResolver(t)
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr")
+	require.NoError(t, err)
+	id, err := IDFromDID(*did)
+	require.NoError(t, err)
+
+	state1, err := NewStateFromBigInt(big.NewInt(1))
+	require.NoError(t, err)
+	state2, err := NewStateFromBigInt(big.NewInt(2))
+	require.NoError(t, err)
+	require.NoError(t, source.PublishState(80002, id, state1,
+		time.Unix(1700000000, 0), 10))
+	require.NoError(t, source.PublishState(80002, id, state2,
+		time.Unix(1700000100, 0), 20))
+
+	didURL, err := DIDURLWithStateQuery(*did, StateQuery{State: &state1})
+	require.NoError(t, err)
+	didURL.Fragment = StateInfoVerificationMethodFragment
+
+	res, err := w3c.NewDereferencer(r).Dereference(context.Background(),
+		*didURL)
+	require.NoError(t, err)
+	vm, ok := res.ContentStream.(*w3c.VerificationMethod)
+	require.True(t, ok)
+	require.Equal(t, "1", vm.Info.State)
+}
+
+func TestIden3Resolver_EthereumControlled(t *testing.T) {
+	r, _ := helperNewResolver(t)
+	did, err := NewDIDFromEthAddress(DIDMethodIden3, Polygon, Amoy,
+		ethAddrFromHex("a51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0"))
+	require.NoError(t, err)
+
+	res, err := r.Resolve(context.Background(), *did, w3c.ResolutionOptions{})
+	require.NoError(t, err)
+	require.NoError(t, res.DIDDocument.Validate())
+	require.Len(t, res.DIDDocument.VerificationMethod, 2)
+	vm := res.DIDDocument.VerificationMethod[1]
+	require.Equal(t, did.String()+"#ethereum-based-id", vm.ID)
+	require.Equal(t, w3c.EcdsaSecp256k1RecoveryMethod2020Type, vm.Type)
+	require.Equal(t,
+		"eip155:80002:0xA51c1fc2f0D1a1b8494Ed1FE312d7C3a78Ed91C0",
+		vm.BlockchainAccountID)
+	require.Len(t, res.DIDDocument.Authentication, 2)
+	require.Contains(t, res.DIDDocument.Context,
+		w3c.EcdsaSecp256k1RecoveryMethod2020Context)
+}
+
+func TestIden3Resolver_ReadOnly(t *testing.T) {
+	r, _ := helperNewResolver(t)
+	did, err := NewReadOnlyDID(DIDMethodIden3, genFromHex(
+		"00000000000000000000000000000000000000000000000000000000000001"))
+	require.NoError(t, err)
+
+	res, err := r.Resolve(context.Background(), *did, w3c.ResolutionOptions{})
+	require.NoError(t, err)
+	require.NoError(t, res.DIDDocument.Validate())
+	require.Empty(t, res.DIDDocument.VerificationMethod)
+}
+
+func TestIden3Resolver_Errors(t *testing.T) {
+	r, _ := helperNewResolver(t)
+
+	testCases := []struct {
+		name string
+		did  string
+		opts w3c.ResolutionOptions
+		code string
+	}{
+		{
+			name: "DID URL",
+			did:  "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr#key",
+			code: w3c.ErrorCodeInvalidDID,
+		},
+		{
+			name: "unknown method",
+			did:  "did:example:123",
+			code: w3c.ErrorCodeMethodNotSupported,
+		},
+		{
+			name: "invalid checksum",
+			did:  "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDs",
+			code: w3c.ErrorCodeInvalidDID,
+		},
+		{
+			name: "no state contract",
+			did:  "did:iden3:polygon:mumbai:wyFiV4w71QgWPn6bYLsZoysFay66gKtVa9kfu6yMZ",
+			code: w3c.ErrorCodeInternalError,
+		},
+		{
+			name: "unsupported representation",
+			did:  "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr",
+			opts: w3c.ResolutionOptions{Accept: "text/html"},
+			code: w3c.ErrorCodeRepresentationNotSupported,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			did, err := w3c.ParseDID(tc.did)
+			require.NoError(t, err)
+			res, err := r.Resolve(context.Background(), *did, tc.opts)
+			var resErr *w3c.ResolutionError
+			require.True(t, errors.As(err, &resErr))
+			require.Equal(t, tc.code, resErr.Code)
+			require.Equal(t, tc.code, res.DIDResolutionMetadata.Error)
+			require.Nil(t, res.DIDDocument)
+		})
+	}
+}
+
+type failingStateSource struct {
+	InMemoryStateSource
+}
+
+func (s *failingStateSource) LatestState(context.Context, ChainID,
+	ID) (*IdentityStateInfo, error) {
+
+	return nil, errors.New("node is down")
+}
+
+func TestIden3Resolver_SourceError(t *testing.T) {
+	r := NewIden3Resolver(&failingStateSource{},
+		map[ChainID][ethAddressLn]byte{80002: testStateContract})
+	did := helperBuildDIDFromType(t, DIDMethodIden3, Polygon, Amoy)
+
+	_, err := r.Resolve(context.Background(), *did, w3c.ResolutionOptions{})
+	var resErr *w3c.ResolutionError
+	require.True(t, errors.As(err, &resErr))
+	require.Equal(t, w3c.ErrorCodeInternalError, resErr.Code)
+	require.EqualError(t, err,
+		"DID resolution error: internalError: node is down")
+}
+
+func TestMultiResolver_Iden3(t *testing.T) {
+	r, _ := helperNewResolver(t)
+	mr := w3c.NewMultiResolver()
+	mr.Register(string(DIDMethodIden3), r)
+	mr.Register(string(DIDMethodPolygonID), r)
+
+	did := helperBuildDIDFromType(t, DIDMethodPolygonID, Polygon, Amoy)
+	res, err := mr.Resolve(context.Background(), *did,
+		w3c.ResolutionOptions{})
+	require.NoError(t, err)
+	require.Equal(t, did.String(), res.DIDDocument.ID.String())
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer. ID is stored as base58 string.
+func (id ID) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts base58 string of ID with valid
+// checksum.
+func (id *ID) Scan(src interface{}) error {
+	s, err := textFromSQL(src, "ID")
+	if err != nil {
+		return err
+	}
+	id2, err := IDFromString(s)
+	if err != nil {
+		return fmt.Errorf("can't scan ID: %w", err)
+	}
+	*id = id2
+	return nil
+}
+
+// Value implements driver.Valuer. SchemaHash is stored as hex string.
+func (sh SchemaHash) Value() (driver.Value, error) {
+	b, err := sh.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner. It accepts hex string of SchemaHash.
+func (sh *SchemaHash) Scan(src interface{}) error {
+	s, err := textFromSQL(src, "SchemaHash")
+	if err != nil {
+		return err
+	}
+	sh2, err := NewSchemaHashFromHex(s)
+	if err != nil {
+		return fmt.Errorf("can't scan SchemaHash: %w", err)
+	}
+	*sh = sh2
+	return nil
+}
+
+// Value implements driver.Valuer. Claim is stored in binary form as returned
+// by MarshalBinary.
+func (c Claim) Value() (driver.Value, error) {
+	return c.MarshalBinary()
+}
+
+// Scan implements sql.Scanner. It accepts binary form of Claim with slots
+// that are valid field elements.
+func (c *Claim) Scan(src interface{}) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("can't scan %T into Claim", src)
+	}
+	var c2 Claim
+	if err := c2.UnmarshalBinary(b); err != nil {
+		return fmt.Errorf("can't scan Claim: %w", err)
+	}
+	*c = c2
+	return nil
+}
+
+// textFromSQL returns text value of database column. NULL is not accepted.
+func textFromSQL(src interface{}, typeName string) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("can't scan %T into %v", src, typeName)
+	}
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/internal/sqltest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQL(t *testing.T) {
+	db, err := sqltest.Open(t.Name())
+	require.NoError(t, err)
+	defer db.Close()
+
+	id := helperIDsFromStates(t, 1)[0]
+	claim, err := NewClaim(AuthSchemaHash, WithIndexID(id),
+		WithRevocationNonce(10))
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO t VALUES (?, ?, ?)", id, AuthSchemaHash,
+		claim)
+	require.NoError(t, err)
+
+	var (
+		id2    ID
+		sh2    SchemaHash
+		claim2 Claim
+	)
+	err = db.QueryRow("SELECT * FROM t").Scan(&id2, &sh2, &claim2)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+	require.Equal(t, AuthSchemaHash, sh2)
+	require.Equal(t, *claim, claim2)
+
+	// values are stored in text and binary forms
+	var idStr, shStr string
+	var claimBytes []byte
+	err = db.QueryRow("SELECT * FROM t").Scan(&idStr, &shStr, &claimBytes)
+	require.NoError(t, err)
+	require.Equal(t, id.String(), idStr)
+	require.Equal(t, "cca3371a6cb1b715004407e325bd993c", shStr)
+	wantBytes, err := claim.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, wantBytes, claimBytes)
+}
+
+func TestSQL_ScanErrors(t *testing.T) {
+	id := helperIDsFromStates(t, 1)[0]
+	badChecksumID := id
+	badChecksumID[29] ^= 1
+
+	testCases := []struct {
+		name string
+		dest interface {
+			Scan(src interface{}) error
+		}
+		src interface{}
+		err string
+	}{
+		{
+			name: "NULL ID",
+			dest: &ID{},
+			src:  nil,
+			err:  "can't scan <nil> into ID",
+		},
+		{
+			name: "invalid ID",
+			dest: &ID{},
+			src:  "0x123",
+			err:  "can't scan ID: invalid base58 digit ('0')",
+		},
+		{
+			name: "ID with invalid checksum",
+			dest: &ID{},
+			src:  []byte(badChecksumID.String()),
+			err:  "can't scan ID: IDFromBytes error: checksum error",
+		},
+		{
+			name: "integer SchemaHash",
+			dest: &SchemaHash{},
+			src:  int64(1),
+			err:  "can't scan int64 into SchemaHash",
+		},
+		{
+			name: "short SchemaHash",
+			dest: &SchemaHash{},
+			src:  "ca93",
+			err:  "can't scan SchemaHash: invalid schema hash length: 2",
+		},
+		{
+			name: "Claim as string",
+			dest: &Claim{},
+			src:  "00",
+			err:  "can't scan string into Claim",
+		},
+		{
+			name: "short Claim",
+			dest: &Claim{},
+			src:  []byte{1, 2, 3},
+			err:  "can't scan Claim: unexpected length of input data",
+		},
+		{
+			name: "Claim slot is not a field element",
+			dest: &Claim{},
+			src:  bytes.Repeat([]byte{0xff}, 256),
+			err:  "can't scan Claim: can't set index slot #0: data does not fits SNARK size",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.EqualError(t, tc.dest.Scan(tc.src), tc.err)
+		})
+	}
+
+	// destination is not changed on error
+	id2 := id
+	require.Error(t, id2.Scan("invalid"))
+	require.Equal(t, id, id2)
+}
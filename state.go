@@ -0,0 +1,146 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/iden3/go-iden3-crypto/utils"
+)
+
+const stateLn = 32
+
+// State is an Identity State. The bytes are stored in little-endian order,
+// the same way as iden3 merkle tree hashes. Smart contracts use the
+// big-endian representation of the same value.
+type State [stateLn]byte
+
+// NewStateFromBigInt creates new State from *big.Int. Returns ErrDataOverflow
+// if value does not fit in Field Q.
+func NewStateFromBigInt(i *big.Int) (State, error) {
+	if i == nil || i.Sign() < 0 {
+		return State{}, errors.New("state must be a non-negative integer")
+	}
+	b, err := fieldIntToBytes(i)
+	if err != nil {
+		return State{}, err
+	}
+	var s State
+	copy(s[:], b)
+	return s, nil
+}
+
+// StateFromTreeRoots calculates the Identity State from the Claims Tree Root,
+// Revocation Tree Root and Roots Tree Root.
+func StateFromTreeRoots(claimsRoot, revRoot, rootsRoot *big.Int) (State,
+	error) {
+
+	state, err := IdenState(claimsRoot, revRoot, rootsRoot)
+	if err != nil {
+		return State{}, err
+	}
+	return NewStateFromBigInt(state)
+}
+
+// NewStateFromHex creates new State from little-endian hex representation
+// used by iden3 libraries.
+func NewStateFromHex(s string) (State, error) {
+	b, err := decodeStateHex(s)
+	if err != nil {
+		return State{}, err
+	}
+	return newStateFromLEBytes(b)
+}
+
+// NewStateFromBigEndianHex creates new State from 0x prefixed big-endian hex
+// representation used by smart contracts. The prefix is optional.
+func NewStateFromBigEndianHex(s string) (State, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	b, err := decodeStateHex(s)
+	if err != nil {
+		return State{}, err
+	}
+	return newStateFromLEBytes(utils.SwapEndianness(b))
+}
+
+// NewStateFromDecimal creates new State from decimal string
+func NewStateFromDecimal(s string) (State, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return State{}, fmt.Errorf("invalid decimal state: %q", s)
+	}
+	return NewStateFromBigInt(i)
+}
+
+// BigInt returns *big.Int representation of State
+func (s State) BigInt() *big.Int {
+	return bytesToInt(s[:])
+}
+
+// Hex returns little-endian hex representation of State used by iden3
+// libraries.
+func (s State) Hex() string {
+	return hex.EncodeToString(s[:])
+}
+
+// BigEndianHex returns 0x prefixed big-endian hex representation of State
+// used by smart contracts.
+func (s State) BigEndianHex() string {
+	b := make([]byte, stateLn)
+	copy(b, s[:])
+	return "0x" + hex.EncodeToString(utils.SwapEndianness(b))
+}
+
+// String returns decimal representation of State used by circuits
+func (s State) String() string {
+	return s.BigInt().Text(10)
+}
+
+// MarshalText returns decimal representation of State
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses State from decimal representation
+func (s *State) UnmarshalText(b []byte) error {
+	s2, err := NewStateFromDecimal(string(b))
+	if err != nil {
+		return err
+	}
+	*s = s2
+	return nil
+}
+
+// NewIDFromState calculates the genesis ID from an Identity State.
+func NewIDFromState(typ DIDType, state State) (ID, error) {
+	id, err := NewIDFromIdenState(typ, state.BigInt())
+	if err != nil {
+		return ID{}, err
+	}
+	return *id, nil
+}
+
+func decodeStateHex(s string) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state hex: %w", err)
+	}
+	if len(b) != stateLn {
+		return nil, fmt.Errorf("invalid state length: %d", len(b))
+	}
+	return b, nil
+}
+
+func newStateFromLEBytes(b []byte) (State, error) {
+	_, err := fieldBytesToInt(b)
+	if err != nil {
+		return State{}, err
+	}
+	var s State
+	copy(s[:], b)
+	return s, nil
+}
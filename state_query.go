@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// DID URL query parameters and resolution method options of StateQuery
+const (
+	// StateQueryParam selects identity state. The value is little-endian hex
+	// of State as returned by State.Hex.
+	StateQueryParam = "state"
+	// GistQueryParam selects GIST root. The value is little-endian hex as
+	// returned by State.Hex.
+	GistQueryParam = "gist"
+	// VersionTimeQueryParam selects the state valid at the time. The value
+	// is RFC 3339 time.
+	VersionTimeQueryParam = w3c.QueryParamVersionTime
+)
+
+// ErrInvalidStateQuery returned when state query parameters are malformed
+var ErrInvalidStateQuery = errors.New("invalid state query")
+
+// StateQuery selects historical identity state and GIST root for resolution.
+// Explicit State and GistRoot take precedence over VersionTime.
+type StateQuery struct {
+	// State selects identity state. Not set if nil.
+	State *State
+	// GistRoot selects GIST root. Not set if nil.
+	GistRoot *State
+	// VersionTime selects identity state and GIST root valid at the time.
+	// Not set if zero.
+	VersionTime time.Time
+}
+
+// IsEmpty returns true if no historical state is selected
+func (q StateQuery) IsEmpty() bool {
+	return q.State == nil && q.GistRoot == nil && q.VersionTime.IsZero()
+}
+
+// ResolutionOptions returns resolution options carrying the query. State and
+// GistRoot are put to MethodOptions.
+func (q StateQuery) ResolutionOptions() w3c.ResolutionOptions {
+	opts := w3c.ResolutionOptions{VersionTime: q.VersionTime}
+	if q.State != nil || q.GistRoot != nil {
+		opts.MethodOptions = make(map[string]interface{})
+	}
+	if q.State != nil {
+		opts.MethodOptions[StateQueryParam] = *q.State
+	}
+	if q.GistRoot != nil {
+		opts.MethodOptions[GistQueryParam] = *q.GistRoot
+	}
+	return opts
+}
+
+// StateQueryFromResolutionOptions returns the query from resolution options.
+// Values of MethodOptions may be State, *State or hex string as in DID URL.
+func StateQueryFromResolutionOptions(
+	opts w3c.ResolutionOptions) (StateQuery, error) {
+
+	q := StateQuery{VersionTime: opts.VersionTime}
+	var err error
+	q.State, err = stateOption(opts.MethodOptions, StateQueryParam)
+	if err != nil {
+		return StateQuery{}, err
+	}
+	q.GistRoot, err = stateOption(opts.MethodOptions, GistQueryParam)
+	if err != nil {
+		return StateQuery{}, err
+	}
+	return q, nil
+}
+
+func stateOption(opts map[string]interface{}, name string) (*State, error) {
+	v, ok := opts[name]
+	if !ok {
+		return nil, nil
+	}
+	switch s := v.(type) {
+	case State:
+		return &s, nil
+	case *State:
+		if s == nil {
+			return nil, nil
+		}
+		s2 := *s
+		return &s2, nil
+	case string:
+		return parseStateParam(name, s)
+	default:
+		return nil, fmt.Errorf("%w: unsupported type of %v: %T",
+			ErrInvalidStateQuery, name, v)
+	}
+}
+
+func parseStateParam(name, value string) (*State, error) {
+	s, err := NewStateFromHex(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v: %v", ErrInvalidStateQuery, name, err)
+	}
+	return &s, nil
+}
+
+// DIDURLWithStateQuery returns copy of DID with query parameters of the
+// state query. Other query parameters of DID are kept in their order, the
+// parameters of the state query are replaced.
+func DIDURLWithStateQuery(did w3c.DID, q StateQuery) (*w3c.DID, error) {
+	params, err := did.QueryParams()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStateQuery, err)
+	}
+
+	params.Del(StateQueryParam)
+	params.Del(GistQueryParam)
+	params.Del(VersionTimeQueryParam)
+	if q.State != nil {
+		params.Set(StateQueryParam, q.State.Hex())
+	}
+	if q.GistRoot != nil {
+		params.Set(GistQueryParam, q.GistRoot.Hex())
+	}
+	if !q.VersionTime.IsZero() {
+		params.SetVersionTime(q.VersionTime)
+	}
+
+	didURL := did
+	didURL.SetQueryParams(params)
+	return w3c.ParseDID(didURL.String())
+}
+
+// StateQueryFromDIDURL returns the state query from DID URL parameters
+func StateQueryFromDIDURL(did w3c.DID) (StateQuery, error) {
+	params, err := did.QueryParams()
+	if err != nil {
+		return StateQuery{}, fmt.Errorf("%w: %v", ErrInvalidStateQuery, err)
+	}
+
+	var q StateQuery
+	if v := params.Get(StateQueryParam); v != "" {
+		q.State, err = parseStateParam(StateQueryParam, v)
+		if err != nil {
+			return StateQuery{}, err
+		}
+	}
+	if v := params.Get(GistQueryParam); v != "" {
+		q.GistRoot, err = parseStateParam(GistQueryParam, v)
+		if err != nil {
+			return StateQuery{}, err
+		}
+	}
+	q.VersionTime, err = params.VersionTime()
+	if err != nil {
+		return StateQuery{}, fmt.Errorf("%w: %v", ErrInvalidStateQuery, err)
+	}
+	return q, nil
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDIDURLWithStateQuery(t *testing.T) {
+	did, err := w3c.ParseDID(
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr?service=x#key")
+	require.NoError(t, err)
+
+	state, err := NewStateFromBigInt(big.NewInt(1))
+	require.NoError(t, err)
+	root, err := NewStateFromBigInt(big.NewInt(258))
+	require.NoError(t, err)
+	q := StateQuery{
+		State:       &state,
+		GistRoot:    &root,
+		VersionTime: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC),
+	}
+	require.False(t, q.IsEmpty())
+	require.True(t, StateQuery{}.IsEmpty())
+
+	didURL, err := DIDURLWithStateQuery(*did, q)
+	require.NoError(t, err)
+	require.Equal(t,
+		"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr"+
+			"?service=x"+
+			"&state=0100000000000000000000000000000000000000000000000000000000000000"+
+			"&gist=0201000000000000000000000000000000000000000000000000000000000000"+
+			"&versionTime=2023-11-14T22:13:20Z#key",
+		didURL.String())
+
+	q2, err := StateQueryFromDIDURL(*didURL)
+	require.NoError(t, err)
+	require.Equal(t, q, q2)
+
+	q2, err = StateQueryFromDIDURL(*did)
+	require.NoError(t, err)
+	require.True(t, q2.IsEmpty())
+
+	// removing of state query
+	didURL, err = DIDURLWithStateQuery(*didURL, StateQuery{})
+	require.NoError(t, err)
+	require.Equal(t, "service=x", didURL.Query)
+}
+
+func TestStateQueryFromDIDURL_Invalid(t *testing.T) {
+	for _, query := range []string{"state=xyz", "gist=0g",
+		"versionTime=yesterday"} {
+
+		did, err := w3c.ParseDID(
+			"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr?" +
+				query)
+		require.NoError(t, err)
+		_, err = StateQueryFromDIDURL(*did)
+		require.ErrorIs(t, err, ErrInvalidStateQuery, query)
+	}
+}
+
+func TestStateQuery_ResolutionOptions(t *testing.T) {
+	state, err := NewStateFromBigInt(big.NewInt(1))
+	require.NoError(t, err)
+	q := StateQuery{State: &state, VersionTime: time.Unix(100, 0)}
+
+	opts := q.ResolutionOptions()
+	require.Equal(t, map[string]interface{}{"state": state},
+		opts.MethodOptions)
+	q2, err := StateQueryFromResolutionOptions(opts)
+	require.NoError(t, err)
+	require.Equal(t, q, q2)
+
+	// values of DID URL are strings
+	q2, err = StateQueryFromResolutionOptions(w3c.ResolutionOptions{
+		MethodOptions: map[string]interface{}{
+			"state": state.Hex(), "gist": &state},
+	})
+	require.NoError(t, err)
+	require.Equal(t, StateQuery{State: &state, GistRoot: &state}, q2)
+
+	_, err = StateQueryFromResolutionOptions(w3c.ResolutionOptions{
+		MethodOptions: map[string]interface{}{"state": 1},
+	})
+	require.ErrorIs(t, err, ErrInvalidStateQuery)
+
+	require.Nil(t, StateQuery{}.ResolutionOptions().MethodOptions)
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrStateNotFound returned by StateSource when identity has no
+	// published state or the state is not found
+	ErrStateNotFound = errors.New("state not found")
+	// ErrGistRootNotFound returned by StateSource when global identity state
+	// tree root is not found
+	ErrGistRootNotFound = errors.New("gist root not found")
+)
+
+// IdentityStateInfo is a record of identity state in the state contract
+type IdentityStateInfo struct {
+	ID    ID
+	State State
+	// ReplacedByState is the next state of identity. Zero if the state is the
+	// latest one.
+	ReplacedByState State
+	CreatedAt       time.Time
+	// ReplacedAt is zero if the state is the latest one
+	ReplacedAt     time.Time
+	CreatedAtBlock uint64
+	// ReplacedAtBlock is zero if the state is the latest one
+	ReplacedAtBlock uint64
+}
+
+// GistRootInfo is a record of global identity state tree (GIST) root in the
+// state contract. GIST root is a field element, so it is encoded as State.
+type GistRootInfo struct {
+	Root State
+	// ReplacedByRoot is the next root. Zero if the root is the latest one.
+	ReplacedByRoot State
+	CreatedAt      time.Time
+	// ReplacedAt is zero if the root is the latest one
+	ReplacedAt     time.Time
+	CreatedAtBlock uint64
+	// ReplacedAtBlock is zero if the root is the latest one
+	ReplacedAtBlock uint64
+}
+
+// StateSource provides identity states and GIST roots published in the state
+// contract of a chain. Implementations must be safe for concurrent use.
+type StateSource interface {
+	// LatestState returns the latest state of identity or ErrStateNotFound
+	LatestState(ctx context.Context, chainID ChainID,
+		id ID) (*IdentityStateInfo, error)
+	// StateInfo returns the given state of identity or ErrStateNotFound
+	StateInfo(ctx context.Context, chainID ChainID, id ID,
+		state State) (*IdentityStateInfo, error)
+	// StateAt returns the state of identity valid at the time or
+	// ErrStateNotFound if identity had no published state at the time
+	StateAt(ctx context.Context, chainID ChainID, id ID,
+		t time.Time) (*IdentityStateInfo, error)
+	// LatestGistRoot returns the latest GIST root or ErrGistRootNotFound
+	LatestGistRoot(ctx context.Context, chainID ChainID) (*GistRootInfo, error)
+	// GistRootInfo returns the given GIST root or ErrGistRootNotFound
+	GistRootInfo(ctx context.Context, chainID ChainID,
+		root State) (*GistRootInfo, error)
+	// GistRootAt returns GIST root valid at the time or ErrGistRootNotFound
+	GistRootAt(ctx context.Context, chainID ChainID,
+		t time.Time) (*GistRootInfo, error)
+}
+
+// InMemoryStateSource is StateSource that keeps states in memory. It may be
+// used instead of the chain in tests.
+type InMemoryStateSource struct {
+	mu     sync.RWMutex
+	states map[ChainID]map[ID][]IdentityStateInfo
+	roots  map[ChainID][]GistRootInfo
+}
+
+// NewInMemoryStateSource creates new empty InMemoryStateSource
+func NewInMemoryStateSource() *InMemoryStateSource {
+	return &InMemoryStateSource{
+		states: make(map[ChainID]map[ID][]IdentityStateInfo),
+		roots:  make(map[ChainID][]GistRootInfo),
+	}
+}
+
+// PublishState adds the latest state of identity. The previous latest state
+// is marked as replaced by this one.
+func (s *InMemoryStateSource) PublishState(chainID ChainID, id ID,
+	state State, createdAt time.Time, block uint64) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.states[chainID] == nil {
+		s.states[chainID] = make(map[ID][]IdentityStateInfo)
+	}
+	history := s.states[chainID][id]
+	for i := range history {
+		if history[i].State == state {
+			return fmt.Errorf("state %v of identity %v is already published",
+				state.String(), id.String())
+		}
+	}
+	if len(history) != 0 {
+		last := &history[len(history)-1]
+		last.ReplacedByState = state
+		last.ReplacedAt = createdAt
+		last.ReplacedAtBlock = block
+	}
+	s.states[chainID][id] = append(history, IdentityStateInfo{
+		ID:             id,
+		State:          state,
+		CreatedAt:      createdAt,
+		CreatedAtBlock: block,
+	})
+	return nil
+}
+
+// PublishGistRoot adds the latest GIST root. The previous latest root is
+// marked as replaced by this one.
+func (s *InMemoryStateSource) PublishGistRoot(chainID ChainID, root State,
+	createdAt time.Time, block uint64) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.roots[chainID]
+	for i := range history {
+		if history[i].Root == root {
+			return fmt.Errorf("gist root %v is already published",
+				root.String())
+		}
+	}
+	if len(history) != 0 {
+		last := &history[len(history)-1]
+		last.ReplacedByRoot = root
+		last.ReplacedAt = createdAt
+		last.ReplacedAtBlock = block
+	}
+	s.roots[chainID] = append(history, GistRootInfo{
+		Root:           root,
+		CreatedAt:      createdAt,
+		CreatedAtBlock: block,
+	})
+	return nil
+}
+
+// LatestState returns the latest state of identity or ErrStateNotFound
+func (s *InMemoryStateSource) LatestState(_ context.Context, chainID ChainID,
+	id ID) (*IdentityStateInfo, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := s.states[chainID][id]
+	if len(history) == 0 {
+		return nil, fmt.Errorf("%w: identity %v", ErrStateNotFound,
+			id.String())
+	}
+	info := history[len(history)-1]
+	return &info, nil
+}
+
+// StateInfo returns the given state of identity or ErrStateNotFound
+func (s *InMemoryStateSource) StateInfo(_ context.Context, chainID ChainID,
+	id ID, state State) (*IdentityStateInfo, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, info := range s.states[chainID][id] {
+		if info.State == state {
+			return &info, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: state %v of identity %v", ErrStateNotFound,
+		state.String(), id.String())
+}
+
+// StateAt returns the state of identity valid at the time or
+// ErrStateNotFound if identity had no published state at the time
+func (s *InMemoryStateSource) StateAt(_ context.Context, chainID ChainID,
+	id ID, t time.Time) (*IdentityStateInfo, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, info := range s.states[chainID][id] {
+		if isValidAt(info.CreatedAt, info.ReplacedAt, t) {
+			return &info, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: identity %v at %v", ErrStateNotFound,
+		id.String(), t.UTC().Format(time.RFC3339))
+}
+
+// LatestGistRoot returns the latest GIST root or ErrGistRootNotFound
+func (s *InMemoryStateSource) LatestGistRoot(_ context.Context,
+	chainID ChainID) (*GistRootInfo, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := s.roots[chainID]
+	if len(history) == 0 {
+		return nil, fmt.Errorf("%w: chain %v", ErrGistRootNotFound, chainID)
+	}
+	info := history[len(history)-1]
+	return &info, nil
+}
+
+// GistRootInfo returns the given GIST root or ErrGistRootNotFound
+func (s *InMemoryStateSource) GistRootInfo(_ context.Context, chainID ChainID,
+	root State) (*GistRootInfo, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, info := range s.roots[chainID] {
+		if info.Root == root {
+			return &info, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrGistRootNotFound, root.String())
+}
+
+// GistRootAt returns GIST root valid at the time or ErrGistRootNotFound
+func (s *InMemoryStateSource) GistRootAt(_ context.Context, chainID ChainID,
+	t time.Time) (*GistRootInfo, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, info := range s.roots[chainID] {
+		if isValidAt(info.CreatedAt, info.ReplacedAt, t) {
+			return &info, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: chain %v at %v", ErrGistRootNotFound,
+		chainID, t.UTC().Format(time.RFC3339))
+}
+
+// isValidAt returns true if record created at createdAt and replaced at
+// replacedAt (zero if not replaced) is valid at t
+func isValidAt(createdAt, replacedAt, t time.Time) bool {
+	return !t.Before(createdAt) && (replacedAt.IsZero() || t.Before(replacedAt))
+}
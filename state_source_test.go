@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStateSource(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStateSource()
+	id := helperIDsFromStates(t, 1)[0]
+
+	_, err := s.LatestState(ctx, 80002, id)
+	require.ErrorIs(t, err, ErrStateNotFound)
+	_, err = s.LatestGistRoot(ctx, 80002)
+	require.ErrorIs(t, err, ErrGistRootNotFound)
+
+	state1, err := NewStateFromBigInt(big.NewInt(1))
+	require.NoError(t, err)
+	state2, err := NewStateFromBigInt(big.NewInt(2))
+	require.NoError(t, err)
+	t1 := time.Unix(100, 0)
+	t2 := time.Unix(200, 0)
+
+	require.NoError(t, s.PublishState(80002, id, state1, t1, 1))
+	require.NoError(t, s.PublishState(80002, id, state2, t2, 2))
+	require.EqualError(t, s.PublishState(80002, id, state1, t2, 3),
+		"state 1 of identity "+id.String()+" is already published")
+
+	latest, err := s.LatestState(ctx, 80002, id)
+	require.NoError(t, err)
+	require.Equal(t, &IdentityStateInfo{ID: id, State: state2,
+		CreatedAt: t2, CreatedAtBlock: 2}, latest)
+
+	info, err := s.StateInfo(ctx, 80002, id, state1)
+	require.NoError(t, err)
+	require.Equal(t, &IdentityStateInfo{ID: id, State: state1,
+		ReplacedByState: state2, CreatedAt: t1, ReplacedAt: t2,
+		CreatedAtBlock: 1, ReplacedAtBlock: 2}, info)
+
+	// states are kept per chain
+	_, err = s.LatestState(ctx, 80001, id)
+	require.ErrorIs(t, err, ErrStateNotFound)
+
+	require.NoError(t, s.PublishGistRoot(80002, state1, t1, 1))
+	require.NoError(t, s.PublishGistRoot(80002, state2, t2, 2))
+	root, err := s.GistRootInfo(ctx, 80002, state1)
+	require.NoError(t, err)
+	require.Equal(t, state2, root.ReplacedByRoot)
+	root, err = s.LatestGistRoot(ctx, 80002)
+	require.NoError(t, err)
+	require.Equal(t, state2, root.Root)
+	_, err = s.GistRootInfo(ctx, 80001, state1)
+	require.ErrorIs(t, err, ErrGistRootNotFound)
+
+	info, err = s.StateAt(ctx, 80002, id, time.Unix(150, 0))
+	require.NoError(t, err)
+	require.Equal(t, state1, info.State)
+	info, err = s.StateAt(ctx, 80002, id, t2)
+	require.NoError(t, err)
+	require.Equal(t, state2, info.State)
+	_, err = s.StateAt(ctx, 80002, id, time.Unix(99, 0))
+	require.ErrorIs(t, err, ErrStateNotFound)
+
+	root, err = s.GistRootAt(ctx, 80002, time.Unix(199, 0))
+	require.NoError(t, err)
+	require.Equal(t, state1, root.Root)
+	root, err = s.GistRootAt(ctx, 80002, time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.Equal(t, state2, root.Root)
+	_, err = s.GistRootAt(ctx, 80002, time.Unix(50, 0))
+	require.ErrorIs(t, err, ErrGistRootNotFound)
+}
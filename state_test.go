@@ -0,0 +1,84 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	state, err := StateFromTreeRoots(big.NewInt(1), big.NewInt(2),
+		big.NewInt(3))
+	require.NoError(t, err)
+
+	wantDec := "6542985608222806190361240322586112750744169038454362455181422643027100751666"
+	require.Equal(t, wantDec, state.String())
+
+	wantInt, ok := new(big.Int).SetString(wantDec, 10)
+	require.True(t, ok)
+	require.Equal(t, wantInt, state.BigInt())
+
+	beHex := state.BigEndianHex()
+	require.Equal(t, fmt.Sprintf("0x%064x", wantInt), beHex)
+
+	leHex := state.Hex()
+	require.Len(t, leHex, 64)
+	require.Equal(t, beHex[len(beHex)-2:], leHex[:2])
+
+	s2, err := NewStateFromHex(leHex)
+	require.NoError(t, err)
+	require.Equal(t, state, s2)
+
+	s2, err = NewStateFromBigEndianHex(beHex)
+	require.NoError(t, err)
+	require.Equal(t, state, s2)
+	s2, err = NewStateFromBigEndianHex(beHex[2:])
+	require.NoError(t, err)
+	require.Equal(t, state, s2)
+
+	s2, err = NewStateFromDecimal(wantDec)
+	require.NoError(t, err)
+	require.Equal(t, state, s2)
+
+	typ, err := BuildDIDType(DIDMethodIden3, Polygon, Amoy)
+	require.NoError(t, err)
+	id, err := NewIDFromState(typ, state)
+	require.NoError(t, err)
+	require.Equal(t, "xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr", id.String())
+}
+
+func TestState_JSON(t *testing.T) {
+	state, err := NewStateFromBigInt(big.NewInt(12345))
+	require.NoError(t, err)
+
+	b, err := json.Marshal(state)
+	require.NoError(t, err)
+	require.Equal(t, `"12345"`, string(b))
+
+	var s2 State
+	require.NoError(t, json.Unmarshal(b, &s2))
+	require.Equal(t, state, s2)
+
+	require.Error(t, json.Unmarshal([]byte(`"0x01"`), &s2))
+}
+
+func TestState_Validation(t *testing.T) {
+	_, err := NewStateFromBigInt(constants.Q)
+	require.ErrorIs(t, err, ErrDataOverflow)
+
+	_, err = NewStateFromBigInt(big.NewInt(-1))
+	require.EqualError(t, err, "state must be a non-negative integer")
+
+	_, err = NewStateFromBigEndianHex(fmt.Sprintf("0x%064x", constants.Q))
+	require.ErrorIs(t, err, ErrDataOverflow)
+
+	_, err = NewStateFromHex("0102")
+	require.EqualError(t, err, "invalid state length: 2")
+
+	_, err = NewStateFromDecimal("abc")
+	require.EqualError(t, err, `invalid decimal state: "abc"`)
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+// ErrUnsupportedDIDNotFound returned by UnsupportedDIDStore when there is no
+// DID for the ID
+var ErrUnsupportedDIDNotFound = errors.New("unsupported DID not found")
+
+// UnsupportedDIDRecord is the DID of unknown method recorded for its ID
+type UnsupportedDIDRecord struct {
+	// DID is the form of DID the ID is calculated from (see
+	// CanonicalUnsupportedDID)
+	DID w3c.DID
+	// Original is the DID string as it was passed to RecordUnsupportedDID
+	Original string
+}
+
+// UnsupportedDIDStore keeps DIDs of unknown methods by IDs that IDFromDID
+// produces for them, so such IDs can be converted back to DIDs.
+// Implementations must be safe for concurrent use.
+type UnsupportedDIDStore interface {
+	// Put records the DID for the ID
+	Put(id ID, rec UnsupportedDIDRecord) error
+	// Get returns the DID for the ID or ErrUnsupportedDIDNotFound
+	Get(id ID) (UnsupportedDIDRecord, error)
+}
+
+var unsupportedDIDStoreLock sync.RWMutex
+
+// unsupportedDIDStore is used by RecordUnsupportedDID and ParseDIDFromID. It
+// can be set using SetUnsupportedDIDStore public function. So it is guarded
+// by unsupportedDIDStoreLock mutex.
+var unsupportedDIDStore UnsupportedDIDStore
+
+// SetUnsupportedDIDStore sets the store where RecordUnsupportedDID records
+// DIDs of unknown methods and where ParseDIDFromID looks them up. Pass nil
+// to disable the lookup. There is no store by default.
+func SetUnsupportedDIDStore(store UnsupportedDIDStore) {
+	unsupportedDIDStoreLock.Lock()
+	defer unsupportedDIDStoreLock.Unlock()
+	unsupportedDIDStore = store
+}
+
+func getUnsupportedDIDStore() UnsupportedDIDStore {
+	unsupportedDIDStoreLock.RLock()
+	defer unsupportedDIDStoreLock.RUnlock()
+	return unsupportedDIDStore
+}
+
+// InMemoryUnsupportedDIDStore is UnsupportedDIDStore that keeps DIDs in
+// memory
+type InMemoryUnsupportedDIDStore struct {
+	mu   sync.RWMutex
+	recs map[ID]UnsupportedDIDRecord
+}
+
+// NewInMemoryUnsupportedDIDStore creates new empty
+// InMemoryUnsupportedDIDStore
+func NewInMemoryUnsupportedDIDStore() *InMemoryUnsupportedDIDStore {
+	return &InMemoryUnsupportedDIDStore{
+		recs: make(map[ID]UnsupportedDIDRecord)}
+}
+
+// Put records the DID for the ID
+func (s *InMemoryUnsupportedDIDStore) Put(id ID,
+	rec UnsupportedDIDRecord) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recs[id] = rec
+	return nil
+}
+
+// Get returns the DID for the ID or ErrUnsupportedDIDNotFound
+func (s *InMemoryUnsupportedDIDStore) Get(id ID) (UnsupportedDIDRecord,
+	error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rec, ok := s.recs[id]
+	if !ok {
+		return UnsupportedDIDRecord{}, fmt.Errorf("%w: %v",
+			ErrUnsupportedDIDNotFound, id.String())
+	}
+	return rec, nil
+}
+
+// RecordUnsupportedDID returns ID of DID as IDFromDID does and, for DIDs of
+// unknown methods, records the DID in UnsupportedDIDStore set by
+// SetUnsupportedDIDStore, so ParseDIDFromID can reverse the mapping. DIDs of
+// known methods are not recorded as ParseDIDFromID doesn't need the store for
+// them.
+func RecordUnsupportedDID(did w3c.DID) (ID, error) {
+	id, err := idFromDID(did)
+	if !errors.Is(err, ErrMethodUnknown) {
+		return id, err
+	}
+
+	store := getUnsupportedDIDStore()
+	if store == nil {
+		return ID{}, errors.New("unsupported DID store is not set")
+	}
+
+	hashed := hashedUnsupportedDID(did)
+	id = newIDFromUnsupportedDID(hashed)
+	err = store.Put(id, UnsupportedDIDRecord{DID: hashed,
+		Original: did.String()})
+	if err != nil {
+		return ID{}, fmt.Errorf("can't record unsupported DID: %w", err)
+	}
+	return id, nil
+}
+
+// LookupUnsupportedDID returns the record of DID of unknown method for the ID
+// from UnsupportedDIDStore. It returns ErrMethodUnknown if there is no store
+// or no record for the ID.
+func LookupUnsupportedDID(id ID) (UnsupportedDIDRecord, error) {
+	store := getUnsupportedDIDStore()
+	if store == nil {
+		return UnsupportedDIDRecord{},
+			fmt.Errorf("%w: unsupported DID", ErrMethodUnknown)
+	}
+
+	rec, err := store.Get(id)
+	if errors.Is(err, ErrUnsupportedDIDNotFound) {
+		return UnsupportedDIDRecord{},
+			fmt.Errorf("%w: unsupported DID", ErrMethodUnknown)
+	} else if err != nil {
+		return UnsupportedDIDRecord{},
+			fmt.Errorf("can't get unsupported DID: %w", err)
+	}
+
+	// the store can't substitute the DID
+	if newIDFromUnsupportedDID(hashedUnsupportedDID(rec.DID)) != id {
+		return UnsupportedDIDRecord{}, fmt.Errorf(
+			"%w: stored DID %v does not match ID %v", ErrIncorrectDID,
+			rec.DID.String(), id.String())
+	}
+	return rec, nil
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+)
+
+var legacyUnsupportedDIDHashingLock sync.RWMutex
+
+// legacyUnsupportedDIDHashing switches newIDFromUnsupportedDID to hashing of
+// DID string as is. It can be modified using SetLegacyUnsupportedDIDHashing
+// public function. So it is guarded by legacyUnsupportedDIDHashingLock mutex.
+var legacyUnsupportedDIDHashing bool
+
+// SetLegacyUnsupportedDIDHashing switches IDFromDID to the legacy mapping of
+// DIDs of unknown methods, where the whole DID URL string is hashed without
+// canonicalization. Use it to keep IDs that were produced by previous
+// versions of the library.
+func SetLegacyUnsupportedDIDHashing(enabled bool) {
+	legacyUnsupportedDIDHashingLock.Lock()
+	defer legacyUnsupportedDIDHashingLock.Unlock()
+	legacyUnsupportedDIDHashing = enabled
+}
+
+func isLegacyUnsupportedDIDHashing() bool {
+	legacyUnsupportedDIDHashingLock.RLock()
+	defer legacyUnsupportedDIDHashingLock.RUnlock()
+	return legacyUnsupportedDIDHashing
+}
+
+// hashedUnsupportedDID returns the form of DID of unknown method that is
+// hashed into ID: the canonical form or the DID as is with legacy hashing
+func hashedUnsupportedDID(did w3c.DID) w3c.DID {
+	if isLegacyUnsupportedDIDHashing() {
+		return did
+	}
+	return CanonicalUnsupportedDID(did)
+}
+
+// CanonicalUnsupportedDID returns the canonical form of DID of unknown method
+// that IDFromDID hashes into ID:
+//   - DID URL components (params, path, query and fragment) are stripped;
+//   - percent-encoded unreserved characters allowed in idchar are decoded and
+//     hex digits of other percent-encoded octets are uppercased;
+//   - case-insensitive parts of known methods are lowercased: host of did:web,
+//     Ethereum address of did:ethr and did:pkh of eip155 namespace.
+func CanonicalUnsupportedDID(did w3c.DID) w3c.DID {
+	// decode first, so the case of decoded characters is normalized too
+	normalized := did.Normalize()
+	canonical := normalized.Base()
+	idStrings := canonical.IDStrings
+
+	switch did.Method {
+	case "web":
+		if len(idStrings) > 0 {
+			idStrings[0] = strings.ToLower(idStrings[0])
+		}
+	case "ethr":
+		if len(idStrings) > 0 && isHexEthAddress(idStrings[len(idStrings)-1]) {
+			idStrings[len(idStrings)-1] =
+				strings.ToLower(idStrings[len(idStrings)-1])
+		}
+	case "pkh":
+		if len(idStrings) == 3 && idStrings[0] == CAIP2NamespaceEIP155 &&
+			isHexEthAddress(idStrings[2]) {
+
+			idStrings[2] = strings.ToLower(idStrings[2])
+		}
+	}
+
+	// normalize again to uppercase hex digits of lowercased percent-encoded
+	// octets
+	return canonical.Normalize()
+}
+
+func isHexEthAddress(s string) bool {
+	if len(s) != 2+2*ethAddressLn || (s[:2] != "0x" && s[:2] != "0X") {
+		return false
+	}
+	_, err := hex.DecodeString(s[2:])
+	return err == nil
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalUnsupportedDID(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"did:something:x", "did:something:x"},
+		{"did:web:example.com#key-1", "did:web:example.com"},
+		{"did:web:example.com/path?query=1", "did:web:example.com"},
+		{"did:web:Example.COM:User", "did:web:example.com:User"},
+		{"did:ethr:0x5:0xA51C1FC2F0D1A1B8494ED1FE312D7C3A78ED91C0",
+			"did:ethr:0x5:0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0"},
+		{"did:pkh:eip155:1:0xA51C1FC2F0D1A1B8494ED1FE312D7C3A78ED91C0",
+			"did:pkh:eip155:1:0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0"},
+		{"did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:CKg5d12Jhpej1JqtmxLJgaFqqeYjxgPqToJ4LBdvG9Ev",
+			"did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:CKg5d12Jhpej1JqtmxLJgaFqqeYjxgPqToJ4LBdvG9Ev"},
+		{"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			did, err := w3c.ParseDID(tc.in)
+			require.NoError(t, err)
+			canonical := CanonicalUnsupportedDID(*did)
+			require.Equal(t, tc.want, canonical.String())
+			_, err = w3c.ParseDID(canonical.String())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCanonicalUnsupportedDID_PercentEncoding(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"did:web:Example.COM%3a8080:%75ser%2f",
+			"did:web:example.com%3A8080:user%2F"},
+		// decoded characters are lowercased as the rest of the host
+		{"did:web:%45xample.com", "did:web:example.com"},
+		{"did:ethr:0x%41%35%31C1FC2F0D1A1B8494ED1FE312D7C3A78ED91C0",
+			"did:ethr:0xa51c1fc2f0d1a1b8494ed1fe312d7c3a78ed91c0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			did, err := w3c.ParseDID(tc.in)
+			require.NoError(t, err)
+			canonical := CanonicalUnsupportedDID(*did)
+			require.Equal(t, tc.want, canonical.String())
+			require.Equal(t, canonical.ID,
+				tc.want[len("did:"+canonical.Method+":"):])
+		})
+	}
+}
+
+func TestIDFromDID_UnsupportedCanonical(t *testing.T) {
+	did1, err := w3c.ParseDID("did:web:example.com#key-1")
+	require.NoError(t, err)
+	did2, err := w3c.ParseDID("did:web:Example.com?versionId=1")
+	require.NoError(t, err)
+
+	id1, err := IDFromDID(*did1)
+	require.NoError(t, err)
+	id2, err := IDFromDID(*did2)
+	require.NoError(t, err)
+	require.Equal(t, id1, id2)
+
+	encoded, err := w3c.ParseDID("did:web:%45xample.com")
+	require.NoError(t, err)
+	id3, err := IDFromDID(*encoded)
+	require.NoError(t, err)
+	require.Equal(t, id1, id3)
+
+	SetLegacyUnsupportedDIDHashing(true)
+	t.Cleanup(func() { SetLegacyUnsupportedDIDHashing(false) })
+
+	legacy1, err := IDFromDID(*did1)
+	require.NoError(t, err)
+	legacy2, err := IDFromDID(*did2)
+	require.NoError(t, err)
+	require.NotEqual(t, legacy1, legacy2)
+	require.NotEqual(t, id1, legacy1)
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/w3c"
+	"github.com/stretchr/testify/require"
+)
+
+type substitutingDIDStore struct {
+	did w3c.DID
+	err error
+}
+
+func (s *substitutingDIDStore) Put(id ID, rec UnsupportedDIDRecord) error {
+	return s.err
+}
+
+func (s *substitutingDIDStore) Get(id ID) (UnsupportedDIDRecord, error) {
+	return UnsupportedDIDRecord{DID: s.did}, s.err
+}
+
+func TestUnsupportedDIDStore(t *testing.T) {
+	did, err := w3c.ParseDID("did:something:x#key-1")
+	require.NoError(t, err)
+
+	// recording is not possible without the store
+	_, err = RecordUnsupportedDID(*did)
+	require.Error(t, err)
+
+	id, err := IDFromDID(*did)
+	require.NoError(t, err)
+	_, err = ParseDIDFromID(id)
+	require.ErrorIs(t, err, ErrMethodUnknown)
+
+	store := NewInMemoryUnsupportedDIDStore()
+	SetUnsupportedDIDStore(store)
+	t.Cleanup(func() { SetUnsupportedDIDStore(nil) })
+
+	// IDFromDID doesn't record DIDs
+	id2, err := IDFromDID(*did)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+	_, err = ParseDIDFromID(id)
+	require.ErrorIs(t, err, ErrMethodUnknown)
+
+	id2, err = RecordUnsupportedDID(*did)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+
+	// canonical DID is returned, the original one is kept in the record
+	did2, err := ParseDIDFromID(id)
+	require.NoError(t, err)
+	require.Equal(t, "did:something:x", did2.String())
+	rec, err := LookupUnsupportedDID(id)
+	require.NoError(t, err)
+	require.Equal(t, "did:something:x", rec.DID.String())
+	require.Equal(t, "did:something:x#key-1", rec.Original)
+
+	// supported DIDs are not recorded
+	iden3DID := helperBuildDIDFromType(t, DIDMethodIden3, Polygon, Amoy)
+	iden3ID, err := RecordUnsupportedDID(*iden3DID)
+	require.NoError(t, err)
+	_, err = store.Get(iden3ID)
+	require.ErrorIs(t, err, ErrUnsupportedDIDNotFound)
+	did3, err := ParseDIDFromID(iden3ID)
+	require.NoError(t, err)
+	require.Equal(t, iden3DID.String(), did3.String())
+}
+
+func TestUnsupportedDIDStore_Errors(t *testing.T) {
+	did, err := w3c.ParseDID("did:something:x")
+	require.NoError(t, err)
+	other, err := w3c.ParseDID("did:something:y")
+	require.NoError(t, err)
+	id := newIDFromUnsupportedDID(*did)
+
+	SetUnsupportedDIDStore(&substitutingDIDStore{did: *other})
+	t.Cleanup(func() { SetUnsupportedDIDStore(nil) })
+
+	_, err = ParseDIDFromID(id)
+	require.ErrorIs(t, err, ErrIncorrectDID)
+
+	storeErr := errors.New("store is down")
+	SetUnsupportedDIDStore(&substitutingDIDStore{err: storeErr})
+
+	// IDFromDID doesn't use the store
+	id2, err := IDFromDID(*did)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+
+	_, err = RecordUnsupportedDID(*did)
+	require.ErrorIs(t, err, storeErr)
+	_, err = ParseDIDFromID(id)
+	require.ErrorIs(t, err, storeErr)
+}
+
+func TestInMemoryUnsupportedDIDStore(t *testing.T) {
+	did, err := w3c.ParseDID("did:something:x")
+	require.NoError(t, err)
+	id := newIDFromUnsupportedDID(*did)
+
+	s := NewInMemoryUnsupportedDIDStore()
+	_, err = s.Get(id)
+	require.ErrorIs(t, err, ErrUnsupportedDIDNotFound)
+
+	rec := UnsupportedDIDRecord{DID: *did, Original: "did:something:x?q=1"}
+	require.NoError(t, s.Put(id, rec))
+	rec2, err := s.Get(id)
+	require.NoError(t, err)
+	require.Equal(t, rec, rec2)
+}
@@ -0,0 +1,192 @@
+package w3c
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidDIDURL returned when DID URL can't be built or its components
+// are malformed
+var ErrInvalidDIDURL = errors.New("invalid DID URL")
+
+// DIDURLBuilder builds DID URLs from unescaped components. Values are
+// percent-encoded as required by the DID URL grammar, so the built DID URL is
+// always parsed back by ParseDID to the same components.
+//
+// The first error is kept by the builder and returned by Build, the calls
+// after the error have no effect.
+//
+//	didURL, err := NewDIDURLBuilder(did).
+//		WithPathSegment("credentials").
+//		WithQueryParam("service", "agent").
+//		WithFragment("key-1").
+//		Build()
+type DIDURLBuilder struct {
+	method    string
+	idStrings []string
+	params    []Param
+	segments  []string
+	query     QueryParams
+	fragment  string
+	err       error
+}
+
+// NewDIDURLBuilder creates new DIDURLBuilder for DID URL of the did. Only
+// method and method-specific-id of did are used, params, path, query and
+// fragment of did are ignored.
+func NewDIDURLBuilder(did DID) *DIDURLBuilder {
+	b := &DIDURLBuilder{method: did.Method}
+	if did.ID != "" {
+		b.idStrings = strings.Split(did.ID, ":")
+	} else {
+		b.idStrings = append([]string(nil), did.IDStrings...)
+	}
+
+	if b.method == "" {
+		return b.fail("method is empty")
+	}
+	for i := 0; i < len(b.method); i++ {
+		if isNotDigit(b.method[i]) && isNotSmallLetter(b.method[i]) {
+			return b.fail("method %q has character that is not a-z OR 0-9",
+				b.method)
+		}
+	}
+	if len(b.idStrings) == 0 {
+		return b.fail("method-specific-id is empty")
+	}
+	for _, idString := range b.idStrings {
+		if idString == "" {
+			return b.fail("idstring must be at least one char long")
+		}
+		for i := 0; i < len(idString); i++ {
+			if idString[i] == '%' {
+				if i+2 >= len(idString) || isNotHexDigit(idString[i+1]) ||
+					isNotHexDigit(idString[i+2]) {
+
+					return b.fail("idstring %q has %% not followed by 2 hex digits",
+						idString)
+				}
+				i += 2
+				continue
+			}
+			if isNotValidIDChar(idString[i]) {
+				return b.fail(
+					"idstring %q has character that is not ALPHA OR DIGIT OR '.' OR '-'",
+					idString)
+			}
+		}
+	}
+	return b
+}
+
+// WithParam adds DID parameter. Name must not be empty, value may be empty.
+func (b *DIDURLBuilder) WithParam(name, value string) *DIDURLBuilder {
+	if b.err != nil {
+		return b
+	}
+	if name == "" {
+		return b.fail("param name is empty")
+	}
+	b.params = append(b.params, Param{
+		Name:  percentEncode(name, isNotValidParamChar),
+		Value: percentEncode(value, isNotValidParamChar),
+	})
+	return b
+}
+
+// WithPathSegment adds path segment. The segment may contain "/", it is
+// escaped. Only the first segment must not be empty.
+func (b *DIDURLBuilder) WithPathSegment(segment string) *DIDURLBuilder {
+	if b.err != nil {
+		return b
+	}
+	if segment == "" && len(b.segments) == 0 {
+		return b.fail("first path segment is empty")
+	}
+	b.segments = append(b.segments, percentEncode(segment, isNotValidPathChar))
+	return b
+}
+
+// WithQueryParam adds name=value pair to the query. Name must not be empty.
+// The query is encoded by QueryParams.Encode.
+func (b *DIDURLBuilder) WithQueryParam(name, value string) *DIDURLBuilder {
+	if b.err != nil {
+		return b
+	}
+	if name == "" {
+		return b.fail("query param name is empty")
+	}
+	b.query.Add(name, value)
+	return b
+}
+
+// WithFragment sets the fragment. The fragment must not be empty.
+func (b *DIDURLBuilder) WithFragment(fragment string) *DIDURLBuilder {
+	if b.err != nil {
+		return b
+	}
+	if fragment == "" {
+		return b.fail("fragment is empty")
+	}
+	b.fragment = percentEncode(fragment, isNotValidQueryOrFragmentChar)
+	return b
+}
+
+// Build returns the DID URL as parsed by ParseDID or the first error of the
+// builder
+func (b *DIDURLBuilder) Build() (*DID, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	d := DID{
+		Method:       b.method,
+		IDStrings:    b.idStrings,
+		Params:       b.params,
+		PathSegments: b.segments,
+		Query:        b.query.Encode(),
+		Fragment:     b.fragment,
+	}
+	s := d.String()
+	if s == "" {
+		return nil, fmt.Errorf("%w: can't encode DID URL", ErrInvalidDIDURL)
+	}
+	didURL, err := ParseDID(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDIDURL, err)
+	}
+	return didURL, nil
+}
+
+func (b *DIDURLBuilder) fail(format string, args ...interface{}) *DIDURLBuilder {
+	b.err = fmt.Errorf("%w: %v", ErrInvalidDIDURL, fmt.Sprintf(format, args...))
+	return b
+}
+
+// percentEncode returns s with bytes not allowed by isNotAllowed and "%"
+// percent-encoded
+func percentEncode(s string, isNotAllowed func(byte) bool) string {
+	const upperHex = "0123456789ABCDEF"
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		char := s[i]
+		if char != '%' && !isNotAllowed(char) {
+			buf.WriteByte(char)
+			continue
+		}
+		buf.WriteByte('%')
+		buf.WriteByte(upperHex[char>>4])
+		buf.WriteByte(upperHex[char&0x0F])
+	}
+	return buf.String()
+}
+
+// isNotValidQueryParamChar returns true if a byte is not allowed in a name or
+// a value of query parameter. "&", "=", "+" and ";" are delimiters of
+// url.ParseQuery, so they are escaped.
+func isNotValidQueryParamChar(char byte) bool {
+	return isNotValidQueryOrFragmentChar(char) ||
+		char == '&' || char == '=' || char == '+' || char == ';'
+}
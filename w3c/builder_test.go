@@ -0,0 +1,134 @@
+package w3c
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestDIDURLBuilder(t *testing.T) {
+	did, err := ParseDID("did:example:123:456")
+	assert(t, nil, err)
+
+	didURL, err := NewDIDURLBuilder(*did).
+		WithParam("example:param", "a b").
+		WithPathSegment("credentials").
+		WithPathSegment("a/b").
+		WithQueryParam("service", "agent").
+		WithQueryParam("relativeRef", "/path?x=1&y=2+3;4").
+		WithFragment("key 1#2").
+		Build()
+	assert(t, nil, err)
+	assert(t,
+		"did:example:123:456;example:param=a%20b/credentials/a%2Fb"+
+			"?service=agent&relativeRef=/path?x%3D1%26y%3D2%2B3%3B4"+
+			"#key%201%232",
+		didURL.String())
+	assert(t, []string{"123", "456"}, didURL.IDStrings)
+	assert(t, []string{"credentials", "a%2Fb"}, didURL.PathSegments)
+
+	query, err := url.ParseQuery(didURL.Query)
+	assert(t, nil, err)
+	assert(t, "agent", query.Get("service"))
+	assert(t, "/path?x=1&y=2+3;4", query.Get("relativeRef"))
+
+	fragment, err := url.PathUnescape(didURL.Fragment)
+	assert(t, nil, err)
+	assert(t, "key 1#2", fragment)
+
+	// URL parts of the DID are ignored
+	didURL, err = NewDIDURLBuilder(*didURL).WithQueryParam("a", "").Build()
+	assert(t, nil, err)
+	assert(t, "did:example:123:456?a=", didURL.String())
+
+	// the DID built from IDStrings
+	didURL, err = NewDIDURLBuilder(
+		DID{Method: "example", IDStrings: []string{"1", "2"}}).
+		WithParam("p", "").
+		WithPathSegment("a").
+		WithPathSegment("").
+		WithFragment("%").
+		Build()
+	assert(t, nil, err)
+	assert(t, "did:example:1:2;p/a/#%25", didURL.String())
+
+	// percent encoded chars of the parsed DID are kept
+	parsed, err := ParseDID("did:web:example.com%3A3000")
+	assert(t, nil, err)
+	didURL, err = NewDIDURLBuilder(*parsed).WithFragment("k").Build()
+	assert(t, nil, err)
+	assert(t, "did:web:example.com%3A3000#k", didURL.String())
+}
+
+func TestDIDURLBuilder_Errors(t *testing.T) {
+	did := DID{Method: "example", ID: "123"}
+	testCases := []struct {
+		name    string
+		builder *DIDURLBuilder
+		err     string
+	}{
+		{
+			name:    "empty method",
+			builder: NewDIDURLBuilder(DID{ID: "123"}),
+			err:     "invalid DID URL: method is empty",
+		},
+		{
+			name:    "invalid method",
+			builder: NewDIDURLBuilder(DID{Method: "Example", ID: "123"}),
+			err:     `invalid DID URL: method "Example" has character that is not a-z OR 0-9`,
+		},
+		{
+			name:    "empty ID",
+			builder: NewDIDURLBuilder(DID{Method: "example"}),
+			err:     "invalid DID URL: method-specific-id is empty",
+		},
+		{
+			name:    "empty idstring",
+			builder: NewDIDURLBuilder(DID{Method: "example", ID: "1::2"}),
+			err:     "invalid DID URL: idstring must be at least one char long",
+		},
+		{
+			name:    "invalid idstring",
+			builder: NewDIDURLBuilder(DID{Method: "example", ID: "a b"}),
+			err:     `invalid DID URL: idstring "a b" has character that is not ALPHA OR DIGIT OR '.' OR '-'`,
+		},
+		{
+			name:    "invalid percent encoding in idstring",
+			builder: NewDIDURLBuilder(DID{Method: "example", ID: "a%2"}),
+			err:     `invalid DID URL: idstring "a%2" has % not followed by 2 hex digits`,
+		},
+		{
+			name:    "empty param name",
+			builder: NewDIDURLBuilder(did).WithParam("", "x"),
+			err:     "invalid DID URL: param name is empty",
+		},
+		{
+			name:    "empty first path segment",
+			builder: NewDIDURLBuilder(did).WithPathSegment(""),
+			err:     "invalid DID URL: first path segment is empty",
+		},
+		{
+			name:    "empty query param name",
+			builder: NewDIDURLBuilder(did).WithQueryParam("", "x"),
+			err:     "invalid DID URL: query param name is empty",
+		},
+		{
+			name:    "empty fragment",
+			builder: NewDIDURLBuilder(did).WithFragment(""),
+			err:     "invalid DID URL: fragment is empty",
+		},
+		{
+			name: "first error is kept",
+			builder: NewDIDURLBuilder(did).WithParam("", "x").
+				WithFragment(""),
+			err: "invalid DID URL: param name is empty",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.builder.Build()
+			assert(t, true, errors.Is(err, ErrInvalidDIDURL))
+			assert(t, tc.err, err.Error())
+		})
+	}
+}
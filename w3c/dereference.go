@@ -0,0 +1,251 @@
+package w3c
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ContentTypeURIList is the content type of service endpoint URL selected by
+// DID URL dereferencing
+const ContentTypeURIList = "text/uri-list"
+
+// DereferencingError is an error of DID URL dereferencing with error code of
+// dereferencing metadata, e.g. ErrorCodeNotFound or ErrorCodeInvalidDIDURL
+type DereferencingError struct {
+	// Code is one of ErrorCode constants
+	Code string
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *DereferencingError) Error() string {
+	cause := e.Err
+	// the code of resolution error is already in the message
+	if resErr, ok := cause.(*ResolutionError); ok {
+		cause = resErr.Err
+	}
+	if cause == nil {
+		return fmt.Sprintf("DID URL dereferencing error: %v", e.Code)
+	}
+	return fmt.Sprintf("DID URL dereferencing error: %v: %v", e.Code, cause)
+}
+
+func (e *DereferencingError) Unwrap() error {
+	return e.Err
+}
+
+// DereferencingMetadata is metadata of DID URL dereferencing process
+// https://www.w3.org/TR/did-core/#did-url-dereferencing-metadata
+type DereferencingMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DereferencingResult is a result of DID URL dereferencing. ContentStream is
+// one of:
+//   - *DIDDocument for DID URL without path, service and fragment;
+//   - *VerificationMethod or *Service selected by fragment;
+//   - string URL of service endpoint selected by service parameter;
+//   - anything returned by PathHandler.
+type DereferencingResult struct {
+	DereferencingMetadata DereferencingMetadata `json:"dereferencingMetadata"`
+	ContentStream         interface{}           `json:"contentStream"`
+	ContentMetadata       DIDDocumentMetadata   `json:"contentMetadata"`
+}
+
+// PathHandler dereferences DID URLs with path. res is the result of
+// resolution of the DID URL base DID.
+type PathHandler func(ctx context.Context, res ResolutionResult,
+	didURL DID) (DereferencingResult, error)
+
+// Dereferencer dereferences DID URLs using Resolver
+type Dereferencer struct {
+	resolver    Resolver
+	pathHandler PathHandler
+}
+
+// NewDereferencer creates new Dereferencer. DID URLs with path are not
+// supported until PathHandler is set with WithPathHandler.
+func NewDereferencer(resolver Resolver) *Dereferencer {
+	return &Dereferencer{resolver: resolver}
+}
+
+// WithPathHandler returns copy of the Dereferencer that handles DID URLs with
+// path by the handler
+func (d *Dereferencer) WithPathHandler(handler PathHandler) *Dereferencer {
+	d2 := *d
+	d2.pathHandler = handler
+	return &d2
+}
+
+// Dereference resolves the base DID of DID URL and selects the resource of
+// DID URL:
+//   - service endpoint URL by ?service=...[&relativeRef=...] parameters;
+//   - result of PathHandler if DID URL has path;
+//   - verification method or service by #fragment;
+//   - the whole DID document otherwise.
+//
+// versionId and versionTime parameters are passed to resolution options.
+// Other query parameters, except the DID Core ones, are passed to resolution
+// method options as strings.
+// On failure it returns *DereferencingError and the result with the error
+// code set in DereferencingMetadata.
+func (d *Dereferencer) Dereference(ctx context.Context,
+	didURL DID) (DereferencingResult, error) {
+
+	if didURL.Method == "" || methodSpecificID(&didURL) == "" {
+		return newDereferencingErrorResult(ErrorCodeInvalidDIDURL,
+			fmt.Errorf("not a DID URL: %q", didURL.String()))
+	}
+
+	query, err := didURL.QueryParams()
+	if err != nil {
+		return newDereferencingErrorResult(ErrorCodeInvalidDIDURL, err)
+	}
+	if err = query.Validate(); err != nil {
+		return newDereferencingErrorResult(ErrorCodeInvalidDIDURL, err)
+	}
+
+	opts := ResolutionOptions{VersionID: query.VersionID()}
+	opts.VersionTime, err = query.VersionTime()
+	if err != nil {
+		return newDereferencingErrorResult(ErrorCodeInvalidDIDURL, err)
+	}
+
+	for _, p := range query {
+		switch p.Name {
+		case QueryParamService, QueryParamRelativeRef, QueryParamVersionID,
+			QueryParamVersionTime, QueryParamHashLink:
+			continue
+		}
+		if opts.MethodOptions == nil {
+			opts.MethodOptions = make(map[string]interface{})
+		}
+		if _, ok := opts.MethodOptions[p.Name]; !ok {
+			opts.MethodOptions[p.Name] = p.Value
+		}
+	}
+
+	base := DID{Method: didURL.Method, ID: didURL.ID,
+		IDStrings: didURL.IDStrings}
+	res, err := d.resolver.Resolve(ctx, base, opts)
+	if err != nil {
+		return newDereferencingErrorResult(resolutionErrorCode(err))
+	}
+	if res.DIDDocument == nil {
+		return newDereferencingErrorResult(ErrorCodeNotFound,
+			fmt.Errorf("DID document of %v not found", base.String()))
+	}
+	doc := res.DIDDocument
+
+	result := DereferencingResult{
+		DereferencingMetadata: DereferencingMetadata{
+			ContentType: res.DIDResolutionMetadata.ContentType,
+		},
+		ContentMetadata: res.DIDDocumentMetadata,
+	}
+
+	switch {
+	case query.Service() != "":
+		endpoint, err := serviceEndpointURL(doc, query.Service(),
+			query.RelativeRef(), didURL.Fragment)
+		if err != nil {
+			return newDereferencingErrorResult(ErrorCodeNotFound, err)
+		}
+		result.DereferencingMetadata.ContentType = ContentTypeURIList
+		result.ContentStream = endpoint
+	case didURL.Path != "" || len(didURL.PathSegments) != 0:
+		if d.pathHandler == nil {
+			return newDereferencingErrorResult(ErrorCodeNotFound,
+				fmt.Errorf("dereferencing of path is not supported: %q",
+					didURL.String()))
+		}
+		return d.pathHandler(ctx, res, didURL)
+	case didURL.Fragment != "":
+		ref := "#" + didURL.Fragment
+		if vm, ok := doc.VerificationMethodByID(ref); ok {
+			result.ContentStream = vm
+		} else if s, ok := doc.ServiceByID(ref); ok {
+			result.ContentStream = s
+		} else {
+			return newDereferencingErrorResult(ErrorCodeNotFound,
+				fmt.Errorf("fragment %q not found in DID document", ref))
+		}
+	default:
+		result.ContentStream = doc
+	}
+
+	return result, nil
+}
+
+func newDereferencingErrorResult(code string, err error) (DereferencingResult,
+	error) {
+
+	return DereferencingResult{
+		DereferencingMetadata: DereferencingMetadata{Error: code},
+	}, &DereferencingError{Code: code, Err: err}
+}
+
+// resolutionErrorCode maps error of resolution of DID URL base DID to
+// dereferencing error code. The error is returned as is to be the cause.
+func resolutionErrorCode(err error) (string, error) {
+	var resErr *ResolutionError
+	if !errors.As(err, &resErr) {
+		return ErrorCodeInternalError, err
+	}
+	if resErr.Code == ErrorCodeInvalidDID {
+		return ErrorCodeInvalidDIDURL, err
+	}
+	return resErr.Code, err
+}
+
+// serviceEndpointURL selects URL of the service endpoint and resolves
+// relativeRef against it. The fragment of DID URL is appended to the output
+// URL.
+func serviceEndpointURL(doc *DIDDocument, service, relativeRef,
+	fragment string) (string, error) {
+
+	s, ok := doc.ServiceByID("#" + service)
+	if !ok {
+		return "", fmt.Errorf("service %q not found in DID document", service)
+	}
+
+	var endpoint string
+	switch e := s.ServiceEndpoint.(type) {
+	case string:
+		endpoint = e
+	case []interface{}:
+		for _, item := range e {
+			if str, ok := item.(string); ok {
+				endpoint = str
+				break
+			}
+		}
+	case []string:
+		if len(e) != 0 {
+			endpoint = e[0]
+		}
+	}
+	if endpoint == "" {
+		return "", fmt.Errorf("service %q has no URL endpoint", service)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint of service %q: %w", service,
+			err)
+	}
+	if relativeRef != "" {
+		ref, err := url.Parse(relativeRef)
+		if err != nil {
+			return "", fmt.Errorf("invalid relativeRef: %w", err)
+		}
+		u = u.ResolveReference(ref)
+	}
+	if fragment != "" {
+		u.Fragment = fragment
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,188 @@
+package w3c
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type recordingResolver struct {
+	staticResolver
+	opts ResolutionOptions
+}
+
+func (r *recordingResolver) Resolve(ctx context.Context, did DID,
+	opts ResolutionOptions) (ResolutionResult, error) {
+
+	r.opts = opts
+	return r.staticResolver.Resolve(ctx, did, opts)
+}
+
+func helperDereferencer(t *testing.T) (*Dereferencer, *recordingResolver) {
+	t.Helper()
+	var doc DIDDocument
+	err := json.Unmarshal([]byte(testDIDDocument), &doc)
+	assert(t, nil, err)
+	doc.Service = append(doc.Service, Service{
+		ID:              "#files",
+		Type:            "LinkedDomains",
+		ServiceEndpoint: "https://example.com/files/",
+	})
+	r := &recordingResolver{staticResolver: staticResolver{doc: &doc}}
+	return NewDereferencer(r), r
+}
+
+func TestDereference(t *testing.T) {
+	d, r := helperDereferencer(t)
+	ctx := context.Background()
+
+	u, err := ParseDID("did:example:123")
+	assert(t, nil, err)
+	res, err := d.Dereference(ctx, *u)
+	assert(t, nil, err)
+	assert(t, r.doc, res.ContentStream)
+	assert(t, ContentTypeDIDJSON, res.DereferencingMetadata.ContentType)
+
+	u, err = ParseDID("did:example:123#key-1")
+	assert(t, nil, err)
+	res, err = d.Dereference(ctx, *u)
+	assert(t, nil, err)
+	assert(t, &r.doc.VerificationMethod[1], res.ContentStream)
+
+	u, err = ParseDID("did:example:123#recovery")
+	assert(t, nil, err)
+	res, err = d.Dereference(ctx, *u)
+	assert(t, nil, err)
+	assert(t, r.doc.Authentication[1].Method, res.ContentStream)
+
+	u, err = ParseDID("did:example:123#push")
+	assert(t, nil, err)
+	res, err = d.Dereference(ctx, *u)
+	assert(t, nil, err)
+	assert(t, &r.doc.Service[0], res.ContentStream)
+
+	u, err = ParseDID(
+		"did:example:123?service=files&relativeRef=%2Fa%2Fb.json#part")
+	assert(t, nil, err)
+	res, err = d.Dereference(ctx, *u)
+	assert(t, nil, err)
+	assert(t, "https://example.com/a/b.json#part", res.ContentStream)
+	assert(t, ContentTypeURIList, res.DereferencingMetadata.ContentType)
+
+	u, err = ParseDID(
+		"did:example:123?service=files&relativeRef=doc.json")
+	assert(t, nil, err)
+	res, err = d.Dereference(ctx, *u)
+	assert(t, nil, err)
+	assert(t, "https://example.com/files/doc.json", res.ContentStream)
+
+	u, err = ParseDID(
+		"did:example:123?versionId=2&versionTime=2023-11-14T22:15:00Z&state=ab#key-1")
+	assert(t, nil, err)
+	_, err = d.Dereference(ctx, *u)
+	assert(t, nil, err)
+	assert(t, "2", r.opts.VersionID)
+	assert(t, time.Date(2023, 11, 14, 22, 15, 0, 0, time.UTC),
+		r.opts.VersionTime)
+	assert(t, map[string]interface{}{"state": "ab"}, r.opts.MethodOptions)
+}
+
+func TestDereference_Path(t *testing.T) {
+	d, _ := helperDereferencer(t)
+	u, err := ParseDID("did:example:123/some/path")
+	assert(t, nil, err)
+
+	_, err = d.Dereference(context.Background(), *u)
+	var derefErr *DereferencingError
+	assert(t, true, errors.As(err, &derefErr))
+	assert(t, ErrorCodeNotFound, derefErr.Code)
+
+	d = d.WithPathHandler(func(_ context.Context, res ResolutionResult,
+		didURL DID) (DereferencingResult, error) {
+
+		return DereferencingResult{ContentStream: didURL.Path}, nil
+	})
+	res, err := d.Dereference(context.Background(), *u)
+	assert(t, nil, err)
+	assert(t, "some/path", res.ContentStream)
+}
+
+func TestDereference_Errors(t *testing.T) {
+	d, _ := helperDereferencer(t)
+
+	testCases := []struct {
+		name   string
+		didURL DID
+		code   string
+		msg    string
+	}{
+		{
+			name:   "unknown fragment",
+			didURL: DID{Method: "example", ID: "123", Fragment: "key-2"},
+			code:   ErrorCodeNotFound,
+			msg:    `DID URL dereferencing error: notFound: fragment "#key-2" not found in DID document`,
+		},
+		{
+			name:   "unknown service",
+			didURL: DID{Method: "example", ID: "123", Query: "service=mail"},
+			code:   ErrorCodeNotFound,
+			msg:    `DID URL dereferencing error: notFound: service "mail" not found in DID document`,
+		},
+		{
+			name:   "service without URL",
+			didURL: DID{Method: "example", ID: "123", Query: "service=push"},
+			code:   ErrorCodeNotFound,
+			msg:    `DID URL dereferencing error: notFound: service "push" has no URL endpoint`,
+		},
+		{
+			name:   "invalid versionTime",
+			didURL: DID{Method: "example", ID: "123", Query: "versionTime=yesterday"},
+			code:   ErrorCodeInvalidDIDURL,
+			msg:    `DID URL dereferencing error: invalidDidUrl: invalid DID URL: invalid versionTime: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+		},
+		{
+			name:   "relativeRef without service",
+			didURL: DID{Method: "example", ID: "123", Query: "relativeRef=/a"},
+			code:   ErrorCodeInvalidDIDURL,
+			msg:    `DID URL dereferencing error: invalidDidUrl: invalid DID URL: relativeRef is used without service`,
+		},
+		{
+			name:   "invalid query escape",
+			didURL: DID{Method: "example", ID: "123", Query: "service=%zz"},
+			code:   ErrorCodeInvalidDIDURL,
+			msg:    `DID URL dereferencing error: invalidDidUrl: invalid DID URL: query: invalid URL escape "%zz"`,
+		},
+		{
+			name:   "no method",
+			didURL: DID{ID: "123"},
+			code:   ErrorCodeInvalidDIDURL,
+			msg:    `DID URL dereferencing error: invalidDidUrl: not a DID URL: ""`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := d.Dereference(context.Background(), tc.didURL)
+			var derefErr *DereferencingError
+			assert(t, true, errors.As(err, &derefErr))
+			assert(t, tc.code, derefErr.Code)
+			assert(t, tc.msg, err.Error())
+			assert(t, tc.code, res.DereferencingMetadata.Error)
+		})
+	}
+}
+
+func TestDereference_ResolutionError(t *testing.T) {
+	d := NewDereferencer(NewMultiResolver())
+	_, err := d.Dereference(context.Background(),
+		DID{Method: "other", ID: "123", Fragment: "key"})
+	var derefErr *DereferencingError
+	assert(t, true, errors.As(err, &derefErr))
+	assert(t, ErrorCodeMethodNotSupported, derefErr.Code)
+	var resErr *ResolutionError
+	assert(t, true, errors.As(err, &resErr))
+	assert(t,
+		`DID URL dereferencing error: methodNotSupported: no resolver for DID method "other"`,
+		err.Error())
+}
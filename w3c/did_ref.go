@@ -0,0 +1,342 @@
+package w3c
+
+import "strings"
+
+// DIDRef is a parsed DID or DID URL that holds offsets of its components in
+// the input string. Components are returned as substrings of the input, so
+// ParseDIDRef and the accessors of DIDRef do not allocate. DIDRef accepts the
+// same input as ParseDID, use DID to get the DID structure.
+type DIDRef struct {
+	input     string
+	methodEnd int // index of ':' after method
+	idEnd     int // index of the end of method-specific-id
+	paramsEnd int // index of the end of params, including leading ';'
+	pathEnd   int // index of the end of path, including leading '/'
+	queryEnd  int // index of the end of query, including leading '?'
+}
+
+// ParseDIDRef parses the input string into DIDRef. It returns the same errors
+// as ParseDID.
+func ParseDIDRef(input string) (DIDRef, error) {
+	var r DIDRef
+	if err := r.scan(input); err != nil {
+		return DIDRef{}, err
+	}
+	return r, nil
+}
+
+// Validate checks that the input is a valid DID or DID URL. It returns the
+// same errors as ParseDID and does not allocate for valid input.
+func Validate(input string) error {
+	var r DIDRef
+	return r.scan(input)
+}
+
+// String returns the input string of DIDRef
+func (r DIDRef) String() string {
+	return r.input
+}
+
+// Method returns DID method
+func (r DIDRef) Method() string {
+	if r.input == "" {
+		return ""
+	}
+	return r.input[len("did:"):r.methodEnd]
+}
+
+// ID returns method-specific-id, i.e. idstrings separated by ":"
+func (r DIDRef) ID() string {
+	if r.input == "" {
+		return ""
+	}
+	return r.input[r.methodEnd+1 : r.idEnd]
+}
+
+// Base returns the DID of DID URL, i.e. the input without params, path,
+// query and fragment
+func (r DIDRef) Base() string {
+	return r.input[:r.idEnd]
+}
+
+// Params returns params of DID URL separated by ";" without the leading ";"
+func (r DIDRef) Params() string {
+	return r.component(r.idEnd, r.paramsEnd)
+}
+
+// Path returns path of DID URL without the leading "/"
+func (r DIDRef) Path() string {
+	return r.component(r.paramsEnd, r.pathEnd)
+}
+
+// Query returns query of DID URL without the leading "?"
+func (r DIDRef) Query() string {
+	return r.component(r.pathEnd, r.queryEnd)
+}
+
+// Fragment returns fragment of DID URL without the leading "#"
+func (r DIDRef) Fragment() string {
+	return r.component(r.queryEnd, len(r.input))
+}
+
+// IsURL returns true if DIDRef has params, path, query or fragment
+func (r DIDRef) IsURL() bool {
+	return r.Params() != "" || r.Path() != "" || r.Query() != "" ||
+		r.Fragment() != ""
+}
+
+// DID returns the DID structure as returned by ParseDID. It returns nil for
+// zero DIDRef.
+func (r DIDRef) DID() *DID {
+	if r.input == "" {
+		return nil
+	}
+	d := &DID{
+		Method:   r.Method(),
+		ID:       r.ID(),
+		Query:    r.Query(),
+		Fragment: r.Fragment(),
+	}
+	d.IDStrings = strings.Split(d.ID, ":")
+	if params := r.Params(); params != "" {
+		for _, p := range strings.Split(params, ";") {
+			// a repeated "=" starts a new value of the param as in ParseDID
+			parts := strings.Split(p, "=")
+			param := Param{Name: parts[0]}
+			if len(parts) > 1 {
+				param.Value = parts[len(parts)-1]
+			}
+			d.Params = append(d.Params, param)
+		}
+	}
+	if r.pathEnd > r.paramsEnd {
+		d.Path = r.Path()
+		d.PathSegments = strings.Split(d.Path, "/")
+	}
+	return d
+}
+
+// component returns the component between start and end without its leading
+// delimiter
+func (r DIDRef) component(start, end int) string {
+	if end <= start {
+		return ""
+	}
+	return r.input[start+1 : end]
+}
+
+// scan parses the input into offsets of DIDRef. It follows the same grammar
+// and error reporting as the state machine of ParseDID in a single pass.
+// nolint: gocyclo
+func (r *DIDRef) scan(input string) error {
+	inputLength := len(input)
+	if inputLength < 7 {
+		return scanError(input, inputLength, ruleDID, "input length is less than 7")
+	}
+	if input[:4] != "did:" {
+		return scanError(input, 3, ruleDID, "input does not begin with 'did:' prefix")
+	}
+
+	// method = 1*methodchar
+	i := 4
+	for ; ; i++ {
+		if i == inputLength {
+			return scanError(input, i, ruleDID,
+				"input does not have a second `:` marking end of method name")
+		}
+		char := input[i]
+		if char == ':' {
+			if i == 4 {
+				return scanError(input, i, ruleMethod, "method is empty")
+			}
+			break
+		}
+		if isNotDigit(char) && isNotSmallLetter(char) {
+			return scanError(input, i, ruleMethodChar, "character is not a-z OR 0-9")
+		}
+	}
+	methodEnd := i
+
+	// specific-idstring = idstring *( ":" idstring )
+	start := i + 1
+	for i = start; i < inputLength; i++ {
+		char := input[i]
+		if char == ':' || char == ';' || char == '/' || char == '?' ||
+			char == '#' {
+
+			if i == start {
+				return scanError(input, i, ruleIDString,
+					"idstring must be atleast one char long")
+			}
+			if char != ':' {
+				break
+			}
+			start = i + 1
+			continue
+		}
+		if char == '%' {
+			if i+2 >= inputLength || isNotHexDigit(input[i+1]) ||
+				isNotHexDigit(input[i+2]) {
+
+				return scanError(input, i, rulePctEncoded,
+					"%% is not followed by 2 hex digits")
+			}
+			i += 2
+			continue
+		}
+		if isNotValidIDChar(char) {
+			return scanError(input, i, ruleIDChar,
+				"byte is not ALPHA OR DIGIT OR '.' OR '-'")
+		}
+	}
+	if i == start {
+		return scanError(input, i, ruleIDString,
+			"idstring must be atleast one char long")
+	}
+	idEnd := i
+
+	// *( ";" param ), param = param-name [ "=" param-value ]
+	for i < inputLength && input[i] == ';' {
+		start = i + 1
+		inName := true
+		for i = start; i < inputLength; i++ {
+			char := input[i]
+			if char == ';' || char == '/' || char == '?' || char == '#' {
+				break
+			}
+			if char == '=' {
+				if inName && i == start {
+					return scanError(input, i, ruleParamName,
+						"Param name must be at least one char long")
+				}
+				inName = false
+				continue
+			}
+			if char == '%' {
+				if i+2 >= inputLength || isNotHexDigit(input[i+1]) ||
+					isNotHexDigit(input[i+2]) {
+
+					if inName && i == start {
+						return scanError(input, i, ruleParamName,
+							"Param name must be at least one char long")
+					}
+					return scanError(input, i, rulePctEncoded,
+						"%% is not followed by 2 hex digits")
+				}
+				i += 2
+				continue
+			}
+			if isNotValidParamChar(char) {
+				if inName && i == start {
+					return scanError(input, i, ruleParamName,
+						"Param name must be at least one char long")
+				}
+				return scanError(input, i, ruleParamChar,
+					"character is not allowed in param - %c", char)
+			}
+		}
+		if inName && i == start {
+			return scanError(input, i, ruleParamName,
+				"Param name must be at least one char long")
+		}
+	}
+	paramsEnd := i
+
+	// path-abempty = *( "/" segment )
+	if i < inputLength && input[i] == '/' {
+		start = i + 1
+		for i = start; i < inputLength; i++ {
+			char := input[i]
+			if char == '/' {
+				if i == start {
+					return scanError(input, i, rulePath,
+						"first path segment must have atleast one character")
+				}
+				continue
+			}
+			if char == '?' || char == '#' {
+				break
+			}
+			if char == '%' {
+				if i+2 >= inputLength || isNotHexDigit(input[i+1]) ||
+					isNotHexDigit(input[i+2]) {
+
+					return scanError(input, i, rulePctEncoded,
+						"%% is not followed by 2 hex digits")
+				}
+				i += 2
+				continue
+			}
+			if isNotValidPathChar(char) {
+				return scanError(input, i, rulePChar, "character is not allowed in path")
+			}
+		}
+		if i == start {
+			return scanError(input, i, rulePath,
+				"first path segment must have atleast one character")
+		}
+	}
+	pathEnd := i
+
+	// [ "?" query ]
+	if i < inputLength && input[i] == '?' {
+		for i++; i < inputLength && input[i] != '#'; i++ {
+			err := scanQueryOrFragmentChar(input, i, ruleQuery,
+				"character is not allowed in query - %c")
+			if err != nil {
+				return err
+			}
+			if input[i] == '%' {
+				i += 2
+			}
+		}
+	}
+	queryEnd := i
+
+	// [ "#" fragment ]
+	if i < inputLength {
+		for i++; i < inputLength; i++ {
+			err := scanQueryOrFragmentChar(input, i, ruleFragment,
+				"character is not allowed in fragment - %c")
+			if err != nil {
+				return err
+			}
+			if input[i] == '%' {
+				i += 2
+			}
+		}
+	}
+
+	*r = DIDRef{input: input, methodEnd: methodEnd, idEnd: idEnd,
+		paramsEnd: paramsEnd, pathEnd: pathEnd, queryEnd: queryEnd}
+	return nil
+}
+
+// scanQueryOrFragmentChar checks the char of query or fragment at index i.
+// invalidCharRule and invalidCharFormat are the rule and the error format of
+// the char that is not allowed.
+func scanQueryOrFragmentChar(input string, i int, invalidCharRule grammarRule,
+	invalidCharFormat string) error {
+
+	char := input[i]
+	if char == '%' {
+		if i+2 >= len(input) || isNotHexDigit(input[i+1]) ||
+			isNotHexDigit(input[i+2]) {
+
+			return scanError(input, i, rulePctEncoded,
+				"%% is not followed by 2 hex digits")
+		}
+		return nil
+	}
+	if isNotValidQueryOrFragmentChar(char) {
+		return scanError(input, i, invalidCharRule, invalidCharFormat, char)
+	}
+	return nil
+}
+
+// scanError returns *ParseError of DIDRef scanner at the index of the input
+func scanError(input string, index int, rule grammarRule, format string,
+	args ...interface{}) error {
+
+	return newParseError(input, index, rule, format, args...)
+}
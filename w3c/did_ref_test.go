@@ -0,0 +1,175 @@
+package w3c
+
+import (
+	"math/rand"
+	"testing"
+)
+
+var testDIDRefInputs = []string{
+	"did:a:123",
+	"did:example:123:456:789",
+	"did:a:123;p",
+	"did:a:123;p=",
+	"did:a:123;p=1;q:r=2",
+	"did:a:123;p=1=2",
+	"did:a:123;p==",
+	"did:a:123;%41=%42",
+	"did:a:1%3A2:%41",
+	"did:a:123;p/a/b?q=1#f",
+	"did:a:123/a/b/",
+	"did:a:123/a//b",
+	"did:a:123/a%20b",
+	"did:a:123/a#f",
+	"did:a:123?",
+	"did:a:123?q=1&r=/?:@",
+	"did:a:123?q#f",
+	"did:a:123#",
+	"did:a:123#f/?",
+	// invalid
+	"",
+	"did:a",
+	"did:a:",
+	"dxd:a:123",
+	"did::123",
+	"did:aBc:123",
+	"did:abc123",
+	"did:a::123",
+	"did:a:123:",
+	"did:a:1^3",
+	"did:a:1%",
+	"did:a:1%4",
+	"did:a:1%g0:2",
+	"did:a:;p",
+	"did:a:123;",
+	"did:a:123;=1",
+	"did:a:123;^",
+	"did:a:123;%",
+	"did:a:123;%4",
+	"did:a:123;p^",
+	"did:a:123;p=%zz",
+	"did:a:123;p=^",
+	"did:a:123/",
+	"did:a:123//a",
+	"did:a:123/a^",
+	"did:a:123/a%2",
+	"did:a:123/?",
+	"did:a:123/#",
+	"did:a:123?^",
+	"did:a:123?%a",
+	"did:a:123#^",
+	"did:a:123#a#",
+	"did:a:123#%",
+}
+
+func TestParseDIDRef(t *testing.T) {
+	for _, input := range testDIDRefInputs {
+		assertSameAsParseDID(t, input)
+	}
+}
+
+func TestParseDIDRef_Random(t *testing.T) {
+	const alphabet = "did:aZ09.-_~%;=/?#@&^ "
+	rnd := rand.New(rand.NewSource(1))
+	buf := make([]byte, 24)
+	for n := 0; n < 100000; n++ {
+		l := 4 + rnd.Intn(len(buf)-4)
+		copy(buf, "did:")
+		for i := 4; i < l; i++ {
+			buf[i] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		assertSameAsParseDID(t, string(buf[:l]))
+	}
+}
+
+func assertSameAsParseDID(t *testing.T, input string) {
+	t.Helper()
+	expected, expectedErr := ParseDID(input)
+	r, err := ParseDIDRef(input)
+	if expectedErr != nil {
+		parseErr, ok := expectedErr.(*ParseError)
+		assert(t, true, ok && parseErr.Rule != "", "input: %q", input)
+		assert(t, expectedErr, err, "input: %q", input)
+		assert(t, expectedErr, Validate(input), "input: %q", input)
+		assert(t, DIDRef{}, r, "input: %q", input)
+		return
+	}
+	assert(t, nil, err, "input: %q", input)
+	assert(t, nil, Validate(input), "input: %q", input)
+	assert(t, expected, r.DID(), "input: %q", input)
+	assert(t, input, r.String())
+	assert(t, expected.Method, r.Method(), "input: %q", input)
+	assert(t, expected.ID, r.ID(), "input: %q", input)
+	assert(t, expected.Path, r.Path(), "input: %q", input)
+	assert(t, expected.Query, r.Query(), "input: %q", input)
+	assert(t, expected.Fragment, r.Fragment(), "input: %q", input)
+	assert(t, expected.IsURL(), r.IsURL(), "input: %q", input)
+	base := expected.Base()
+	assert(t, base.String(), r.Base(), "input: %q", input)
+}
+
+func TestDIDRef_Components(t *testing.T) {
+	r, err := ParseDIDRef("did:example:123:456;p=1;q/a/b?x=1#key-1")
+	assert(t, nil, err)
+	assert(t, "example", r.Method())
+	assert(t, "123:456", r.ID())
+	assert(t, "did:example:123:456", r.Base())
+	assert(t, "p=1;q", r.Params())
+	assert(t, "a/b", r.Path())
+	assert(t, "x=1", r.Query())
+	assert(t, "key-1", r.Fragment())
+	assert(t, true, r.IsURL())
+
+	var zero DIDRef
+	assert(t, "", zero.Method())
+	assert(t, "", zero.ID())
+	assert(t, "", zero.String())
+	assert(t, (*DID)(nil), zero.DID())
+}
+
+func TestValidate_Allocations(t *testing.T) {
+	input := "did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr;p=1/a/b?versionTime=2023-11-14T22:15:00Z#state-info"
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := Validate(input); err != nil {
+			t.Fatal(err)
+		}
+		r, err := ParseDIDRef(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = r.Fragment()
+	})
+	assert(t, float64(0), allocs)
+}
+
+var benchmarkDIDs = []string{
+	"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr",
+	"did:iden3:polygon:amoy:xH5e6PJBokSxcsAqcpeeU8St848PcHaMUKPJxhnDr/a/b?state=01#state-info",
+	"did:web:example.com:user:alice",
+}
+
+func BenchmarkParseDID(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		if _, err := ParseDID(benchmarkDIDs[n%len(benchmarkDIDs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseDIDRef(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		if _, err := ParseDIDRef(benchmarkDIDs[n%len(benchmarkDIDs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		if err := Validate(benchmarkDIDs[n%len(benchmarkDIDs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
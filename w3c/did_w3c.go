@@ -3,10 +3,7 @@
 // Got from https://github.com/build-trust/did
 package w3c
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
 // Param represents a parsed DID param,
 // which contains a name and value. A generic param is defined
@@ -153,7 +150,7 @@ func (d *DID) String() string {
 	}
 
 	if d.Fragment != "" {
-		// add fragment only when there is no path
+		// write a leading # and then Fragment
 		buf.WriteByte('#')          // nolint, returned error is always nil
 		buf.WriteString(d.Fragment) // nolint, returned error is always nil
 	}
@@ -208,7 +205,7 @@ func (p *parser) checkLength() parserStep {
 	inputLength := len(p.input)
 
 	if inputLength < 7 {
-		return p.errorf(inputLength, "input length is less than 7")
+		return p.errorf(inputLength, ruleDID, "input length is less than 7")
 	}
 
 	return p.parseScheme
@@ -221,7 +218,7 @@ func (p *parser) parseScheme() parserStep {
 
 	// the grammar requires `did:` prefix
 	if p.input[:currentIndex+1] != "did:" {
-		return p.errorf(currentIndex, "input does not begin with 'did:' prefix")
+		return p.errorf(currentIndex, ruleDID, "input does not begin with 'did:' prefix")
 	}
 
 	p.currentIndex = currentIndex
@@ -246,7 +243,7 @@ func (p *parser) parseMethod() parserStep {
 	for {
 		if currentIndex == inputLength {
 			// we got to the end of the input and didn't find a second ':'
-			return p.errorf(currentIndex, "input does not have a second `:` marking end of method name")
+			return p.errorf(currentIndex, ruleDID, "input does not have a second `:` marking end of method name")
 		}
 
 		// read the input character at currentIndex
@@ -256,14 +253,14 @@ func (p *parser) parseMethod() parserStep {
 			// we've found the second : in the input that marks the end of the method
 			if currentIndex == startIndex {
 				// return error is method is empty, ex- did::1234
-				return p.errorf(currentIndex, "method is empty")
+				return p.errorf(currentIndex, ruleMethod, "method is empty")
 			}
 			break
 		}
 
 		// as per the grammar method can only be made of digits 0-9 or small letters a-z
 		if isNotDigit(char) && isNotSmallLetter(char) {
-			return p.errorf(currentIndex, "character is not a-z OR 0-9")
+			return p.errorf(currentIndex, ruleMethodChar, "character is not a-z OR 0-9")
 		}
 
 		// move to the next char
@@ -284,7 +281,8 @@ func (p *parser) parseMethod() parserStep {
 //
 //	specific-idstring = idstring *( ":" idstring )
 //	idstring          = 1*idchar
-//	idchar            = ALPHA / DIGIT / "." / "-"
+//	idchar            = ALPHA / DIGIT / "." / "-" / pct-encoded
+//	pct-encoded       = "%" HEXDIG HEXDIG
 //
 // p.out.IDStrings is later concatented by the ParseDID function before it returns.
 func (p *parser) parseID() parserStep {
@@ -334,10 +332,22 @@ func (p *parser) parseID() parserStep {
 			break
 		}
 
+		if char == '%' {
+			// a % must be followed by 2 hex digits
+			if (currentIndex+2 >= inputLength) ||
+				isNotHexDigit(input[currentIndex+1]) ||
+				isNotHexDigit(input[currentIndex+2]) {
+				return p.errorf(currentIndex, rulePctEncoded, "%% is not followed by 2 hex digits")
+			}
+			// percent encoded char, jump three chars
+			currentIndex = currentIndex + 3
+			continue
+		}
+
 		// make sure current char is a valid idchar
-		// idchar = ALPHA / DIGIT / "." / "-"
+		// idchar = ALPHA / DIGIT / "." / "-" / pct-encoded
 		if isNotValidIDChar(char) {
-			return p.errorf(currentIndex, "byte is not ALPHA OR DIGIT OR '.' OR '-'")
+			return p.errorf(currentIndex, ruleIDChar, "byte is not ALPHA OR DIGIT OR '.' OR '-'")
 		}
 
 		// move to the next char
@@ -349,7 +359,7 @@ func (p *parser) parseID() parserStep {
 		// from the grammar:
 		//   idstring = 1*idchar
 		// return error because idstring is empty, ex- did:a::123:456
-		return p.errorf(currentIndex, "idstring must be atleast one char long")
+		return p.errorf(currentIndex, ruleIDString, "idstring must be atleast one char long")
 	}
 
 	// set parser state
@@ -378,7 +388,7 @@ func (p *parser) parseParamName() parserStep {
 		// from the grammar:
 		//   1*param-char
 		// return error because param-name is empty, ex- did:a::123:456;param-name
-		return p.errorf(currentIndex, "Param name must be at least one char long")
+		return p.errorf(currentIndex, ruleParamName, "Param name must be at least one char long")
 	}
 
 	// Create a new param with the name
@@ -466,7 +476,7 @@ func (p *parser) paramTransition() parserStep {
 			if (currentIndex+2 >= inputLength) ||
 				isNotHexDigit(input[currentIndex+1]) ||
 				isNotHexDigit(input[currentIndex+2]) {
-				return p.errorf(currentIndex, "%% is not followed by 2 hex digits")
+				return p.errorf(currentIndex, rulePctEncoded, "%% is not followed by 2 hex digits")
 			}
 			// if we got here, we're dealing with percent encoded char, jump three chars
 			percentEncoded = true
@@ -480,7 +490,7 @@ func (p *parser) paramTransition() parserStep {
 		// make sure current char is a valid param-char
 		// idchar = ALPHA / DIGIT / "." / "-"
 		if !percentEncoded && isNotValidParamChar(char) {
-			return p.errorf(currentIndex, "character is not allowed in param - %c", char)
+			return p.errorf(currentIndex, ruleParamChar, "character is not allowed in param - %c", char)
 		}
 
 		// move to the next char
@@ -535,12 +545,18 @@ func (p *parser) parsePath() parserStep {
 			break
 		}
 
+		if char == '#' {
+			// encountered # input may have a fragment following path, parse that next
+			next = p.parseFragment
+			break
+		}
+
 		if char == '%' {
 			// a % must be followed by 2 hex digits
 			if (currentIndex+2 >= inputLength) ||
 				isNotHexDigit(input[currentIndex+1]) ||
 				isNotHexDigit(input[currentIndex+2]) {
-				return p.errorf(currentIndex, "%% is not followed by 2 hex digits")
+				return p.errorf(currentIndex, rulePctEncoded, "%% is not followed by 2 hex digits")
 			}
 			// if we got here, we're dealing with percent encoded char, jump three chars
 			percentEncoded = true
@@ -553,7 +569,7 @@ func (p *parser) parsePath() parserStep {
 
 		// pchar = unreserved / pct-encoded / sub-delims / ":" / "@"
 		if !percentEncoded && isNotValidPathChar(char) {
-			return p.errorf(currentIndex, "character is not allowed in path")
+			return p.errorf(currentIndex, rulePChar, "character is not allowed in path")
 		}
 
 		// move to the next char
@@ -565,7 +581,7 @@ func (p *parser) parsePath() parserStep {
 		// first path segment must have atleast one character
 		// from the grammar
 		//   did-path = segment-nz *( "/" segment )
-		return p.errorf(currentIndex, "first path segment must have atleast one character")
+		return p.errorf(currentIndex, rulePath, "first path segment must have atleast one character")
 	}
 
 	// update parser state
@@ -614,7 +630,7 @@ func (p *parser) parseQuery() parserStep {
 			if (currentIndex+2 >= inputLength) ||
 				isNotHexDigit(input[currentIndex+1]) ||
 				isNotHexDigit(input[currentIndex+2]) {
-				return p.errorf(currentIndex, "%% is not followed by 2 hex digits")
+				return p.errorf(currentIndex, rulePctEncoded, "%% is not followed by 2 hex digits")
 			}
 			// if we got here, we're dealing with percent encoded char, jump three chars
 			percentEncoded = true
@@ -629,7 +645,7 @@ func (p *parser) parseQuery() parserStep {
 		// pchar = unreserved / pct-encoded / sub-delims / ":" / "@"
 		// isNotValidQueryOrFragmentChar checks for all the valid chars except pct-encoded
 		if !percentEncoded && isNotValidQueryOrFragmentChar(char) {
-			return p.errorf(currentIndex, "character is not allowed in query - %c", char)
+			return p.errorf(currentIndex, ruleQuery, "character is not allowed in query - %c", char)
 		}
 
 		// move to the next char
@@ -675,7 +691,7 @@ func (p *parser) parseFragment() parserStep {
 			if (currentIndex+2 >= inputLength) ||
 				isNotHexDigit(input[currentIndex+1]) ||
 				isNotHexDigit(input[currentIndex+2]) {
-				return p.errorf(currentIndex, "%% is not followed by 2 hex digits")
+				return p.errorf(currentIndex, rulePctEncoded, "%% is not followed by 2 hex digits")
 			}
 			// if we got here, we're dealing with percent encoded char, jump three chars
 			percentEncoded = true
@@ -690,7 +706,7 @@ func (p *parser) parseFragment() parserStep {
 		// pchar = unreserved / pct-encoded / sub-delims / ":" / "@"
 		// isNotValidQueryOrFragmentChar checks for all the valid chars except pct-encoded
 		if !percentEncoded && isNotValidQueryOrFragmentChar(char) {
-			return p.errorf(currentIndex, "character is not allowed in fragment - %c", char)
+			return p.errorf(currentIndex, ruleFragment, "character is not allowed in fragment - %c", char)
 		}
 
 		// move to the next char
@@ -709,9 +725,12 @@ func (p *parser) parseFragment() parserStep {
 // errorf is a parserStep that returns nil to cause the state machine to exit
 // before returning it sets the currentIndex and err field in parser state
 // other parser steps use this function to exit the state machine with an error
-func (p *parser) errorf(index int, format string, args ...interface{}) parserStep {
+// the error is *ParseError at the index of the input
+func (p *parser) errorf(index int, rule grammarRule, format string,
+	args ...interface{}) parserStep {
+
 	p.currentIndex = index
-	p.err = fmt.Errorf(format, args...)
+	p.err = newParseError(p.input, index, rule, format, args...)
 	return nil
 }
 
@@ -722,7 +741,9 @@ func (p *parser) errorf(index int, format string, args ...interface{}) parserSte
 // isNotValidIDChar returns true if a byte is not allowed in a ID
 // from the grammar:
 //
-//	idchar = ALPHA / DIGIT / "." / "-"
+//	idchar = ALPHA / DIGIT / "." / "-" / pct-encoded
+//
+// pct-encoded is not checked in this function
 func isNotValidIDChar(char byte) bool {
 	return isNotAlpha(char) && isNotDigit(char) && char != '.' && char != '-'
 }
@@ -199,6 +199,27 @@ func TestParse(t *testing.T) {
 		}
 	})
 
+	t.Run("succeeds with percent encoded chars in id", func(t *testing.T) {
+		d, err := ParseDID("did:web:example.com%3A3000:u%2fb")
+		assert(t, nil, err)
+		assert(t, "example.com%3A3000:u%2fb", d.ID)
+		assert(t, "example.com%3A3000", d.IDStrings[0])
+		assert(t, "u%2fb", d.IDStrings[1])
+	})
+
+	t.Run("returns error if percent encoded char in id is invalid", func(t *testing.T) {
+		dids := []string{
+			"did:a:%",
+			"did:a:1%4",
+			"did:a:%zz",
+			"did:a:1%4:2",
+		}
+		for _, did := range dids {
+			_, err := ParseDID(did)
+			assert(t, false, err == nil, "Input: %s", did)
+		}
+	})
+
 	t.Run("returns error if input does not begin with did: scheme", func(t *testing.T) {
 		_, err := ParseDID("a:12345")
 		assert(t, false, err == nil)
@@ -500,6 +521,14 @@ func TestParse(t *testing.T) {
 		assert(t, "abc", d.Query)
 	})
 
+	t.Run("succeeds to extract fragment after path", func(t *testing.T) {
+		d, err := ParseDID("did:a:123/a/b#xyz")
+		assert(t, nil, err)
+		assert(t, "a/b", d.Path)
+		assert(t, "xyz", d.Fragment)
+		assert(t, "did:a:123/a/b#xyz", d.String())
+	})
+
 	t.Run("succeeds to extract fragment after query", func(t *testing.T) {
 		d, err := ParseDID("did:a:123?abc#xyz")
 		assert(t, nil, err)
@@ -568,7 +597,7 @@ func TestParse(t *testing.T) {
 
 func Test_errorf(t *testing.T) {
 	p := &parser{}
-	p.errorf(10, "%s,%s", "a", "b")
+	p.errorf(10, ruleDID, "%s,%s", "a", "b")
 
 	if p.currentIndex != 10 {
 		t.Errorf("did not set currentIndex")
@@ -0,0 +1,574 @@
+package w3c
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Contexts of DID documents and verification methods
+const (
+	// DIDContextV1 is the context of DID Core documents. It must be the first
+	// context of JSON-LD representation of DID document.
+	DIDContextV1 = "https://www.w3.org/ns/did/v1"
+	// JSONWebKey2020Context is the context of JsonWebKey2020 verification
+	// method
+	JSONWebKey2020Context = "https://w3id.org/security/suites/jws-2020/v1"
+	// EcdsaSecp256k1RecoveryMethod2020Context is the context of
+	// EcdsaSecp256k1RecoveryMethod2020 verification method
+	EcdsaSecp256k1RecoveryMethod2020Context = "https://w3id.org/security/suites/secp256k1recovery-2020/v2"
+	// Iden3StateInfo2023Context is the context of Iden3StateInfo2023
+	// verification method
+	Iden3StateInfo2023Context = "https://schema.iden3.io/core/jsonld/auth.jsonld"
+)
+
+// Types of verification methods
+const (
+	// JSONWebKey2020Type is a verification method with public key in JWK
+	// format
+	JSONWebKey2020Type = "JsonWebKey2020"
+	// EcdsaSecp256k1RecoveryMethod2020Type is a verification method of
+	// secp256k1 keys identified by blockchain account
+	EcdsaSecp256k1RecoveryMethod2020Type = "EcdsaSecp256k1RecoveryMethod2020"
+	// Iden3StateInfo2023Type is a verification method with iden3 identity
+	// state published in the state contract
+	Iden3StateInfo2023Type = "Iden3StateInfo2023"
+)
+
+// ErrInvalidDIDDocument returned by DIDDocument.Validate
+var ErrInvalidDIDDocument = errors.New("invalid DID document")
+
+// DIDDocument is a DID document
+// https://www.w3.org/TR/did-core/#core-properties
+type DIDDocument struct {
+	// Context is the JSON-LD @context. Elements are strings or objects.
+	Context []interface{}
+	// ID is the DID subject
+	ID DID
+	// Controller is a set of DIDs of the document controllers
+	Controller []DID
+	// AlsoKnownAs is a set of other URIs of the DID subject
+	AlsoKnownAs []string
+	// VerificationMethod is a set of verification methods
+	VerificationMethod []VerificationMethod
+	// Authentication is a set of verification methods used for
+	// authentication of the DID subject
+	Authentication []VerificationRelationship
+	// AssertionMethod is a set of verification methods used for issuing
+	// verifiable credentials
+	AssertionMethod []VerificationRelationship
+	// Service is a set of services of the DID subject
+	Service []Service
+}
+
+// VerificationMethod is a verification method of DID document. It holds
+// properties of all supported verification method types, only the ones of
+// the Type are set.
+// https://www.w3.org/TR/did-core/#verification-methods
+type VerificationMethod struct {
+	// ID is a DID URL with fragment. It may be relative to the document DID,
+	// e.g. #key-1.
+	ID string `json:"id"`
+	// Type is a type of verification method
+	Type string `json:"type"`
+	// Controller is DID of the verification method controller
+	Controller string `json:"controller"`
+
+	// PublicKeyJwk is a public key in JWK format, used by JsonWebKey2020 and
+	// EcdsaSecp256k1RecoveryMethod2020
+	PublicKeyJwk map[string]interface{} `json:"publicKeyJwk,omitempty"`
+	// BlockchainAccountID is CAIP-10 account ID, used by
+	// EcdsaSecp256k1RecoveryMethod2020
+	BlockchainAccountID string `json:"blockchainAccountId,omitempty"`
+	// EthereumAddress is hex Ethereum address, used by
+	// EcdsaSecp256k1RecoveryMethod2020
+	EthereumAddress string `json:"ethereumAddress,omitempty"`
+
+	// StateContractAddress is CAIP-10 account ID of the state contract, used
+	// by Iden3StateInfo2023
+	StateContractAddress string `json:"stateContractAddress,omitempty"`
+	// Published is true if identity state is published in the state
+	// contract, used by Iden3StateInfo2023
+	Published *bool `json:"published,omitempty"`
+	// Info is the identity state, used by Iden3StateInfo2023
+	Info *StateInfo `json:"info,omitempty"`
+	// Global is the global identity state tree root, used by
+	// Iden3StateInfo2023
+	Global *GistInfo `json:"global,omitempty"`
+}
+
+// StateInfo is the identity state in the state contract. Numbers are
+// decimal strings.
+type StateInfo struct {
+	ID                  string `json:"id"`
+	State               string `json:"state"`
+	ReplacedByState     string `json:"replacedByState"`
+	CreatedAtTimestamp  string `json:"createdAtTimestamp"`
+	ReplacedAtTimestamp string `json:"replacedAtTimestamp"`
+	CreatedAtBlock      string `json:"createdAtBlock"`
+	ReplacedAtBlock     string `json:"replacedAtBlock"`
+}
+
+// GistInfo is the global identity state tree root in the state contract.
+// Numbers are decimal strings.
+type GistInfo struct {
+	Root                string `json:"root"`
+	ReplacedByRoot      string `json:"replacedByRoot"`
+	CreatedAtTimestamp  string `json:"createdAtTimestamp"`
+	ReplacedAtTimestamp string `json:"replacedAtTimestamp"`
+	CreatedAtBlock      string `json:"createdAtBlock"`
+	ReplacedAtBlock     string `json:"replacedAtBlock"`
+}
+
+// VerificationRelationship is an element of verification relationship like
+// authentication or assertionMethod. It is either a reference to
+// verification method by DID URL or embedded verification method.
+// https://www.w3.org/TR/did-core/#verification-relationships
+type VerificationRelationship struct {
+	// Reference is DID URL of verification method. It may be relative to
+	// the document DID, e.g. #key-1.
+	Reference string
+	// Method is embedded verification method. It is set if Reference is
+	// empty.
+	Method *VerificationMethod
+}
+
+// Service is a service of DID document
+// https://www.w3.org/TR/did-core/#services
+type Service struct {
+	// ID is a DID URL with fragment. It may be relative to the document DID,
+	// e.g. #service-1.
+	ID string `json:"id"`
+	// Type is a type of service
+	Type string `json:"type"`
+	// ServiceEndpoint is a string, a map or a set of strings and maps
+	ServiceEndpoint interface{} `json:"serviceEndpoint"`
+}
+
+// MarshalJSON returns verification method reference as JSON string or
+// embedded verification method as JSON object
+func (r VerificationRelationship) MarshalJSON() ([]byte, error) {
+	if r.Method != nil {
+		return json.Marshal(r.Method)
+	}
+	return json.Marshal(r.Reference)
+}
+
+// UnmarshalJSON parses verification method reference or embedded
+// verification method
+func (r *VerificationRelationship) UnmarshalJSON(b []byte) error {
+	var ref string
+	if err := json.Unmarshal(b, &ref); err == nil {
+		*r = VerificationRelationship{Reference: ref}
+		return nil
+	}
+
+	var vm VerificationMethod
+	err := json.Unmarshal(b, &vm)
+	if err != nil {
+		return err
+	}
+	*r = VerificationRelationship{Method: &vm}
+	return nil
+}
+
+type didDocumentJSON struct {
+	Context            interface{}                `json:"@context,omitempty"`
+	ID                 DID                        `json:"id"`
+	Controller         interface{}                `json:"controller,omitempty"`
+	AlsoKnownAs        []string                   `json:"alsoKnownAs,omitempty"`
+	VerificationMethod []VerificationMethod       `json:"verificationMethod,omitempty"`
+	Authentication     []VerificationRelationship `json:"authentication,omitempty"`
+	AssertionMethod    []VerificationRelationship `json:"assertionMethod,omitempty"`
+	Service            []Service                  `json:"service,omitempty"`
+}
+
+// MarshalJSON returns JSON representation of DID document. @context is
+// written only if Context is set. Use MarshalJSONLD to get JSON-LD
+// representation.
+func (doc DIDDocument) MarshalJSON() ([]byte, error) {
+	j := didDocumentJSON{
+		ID:                 doc.ID,
+		AlsoKnownAs:        doc.AlsoKnownAs,
+		VerificationMethod: doc.VerificationMethod,
+		Authentication:     doc.Authentication,
+		AssertionMethod:    doc.AssertionMethod,
+		Service:            doc.Service,
+	}
+
+	switch len(doc.Context) {
+	case 0:
+	case 1:
+		j.Context = doc.Context[0]
+	default:
+		j.Context = doc.Context
+	}
+
+	switch len(doc.Controller) {
+	case 0:
+	case 1:
+		j.Controller = doc.Controller[0]
+	default:
+		j.Controller = doc.Controller
+	}
+
+	return json.Marshal(j)
+}
+
+// MarshalJSONLD returns JSON-LD representation of DID document. DID Core
+// context is put first and contexts of used verification method types are
+// appended to the Context if missing.
+func (doc DIDDocument) MarshalJSONLD() ([]byte, error) {
+	doc.Context = doc.JSONLDContext()
+	return json.Marshal(doc)
+}
+
+// JSONLDContext returns the @context of JSON-LD representation of DID
+// document: DID Core context, other contexts of the document and contexts of
+// used verification method types.
+func (doc DIDDocument) JSONLDContext() []interface{} {
+	ctx := []interface{}{DIDContextV1}
+	for _, c := range doc.Context {
+		if c != DIDContextV1 {
+			ctx = append(ctx, c)
+		}
+	}
+
+	hasContext := func(c string) bool {
+		for _, c2 := range ctx {
+			if c2 == c {
+				return true
+			}
+		}
+		return false
+	}
+	addVMContext := func(vm *VerificationMethod) {
+		var c string
+		switch vm.Type {
+		case JSONWebKey2020Type:
+			c = JSONWebKey2020Context
+		case EcdsaSecp256k1RecoveryMethod2020Type:
+			c = EcdsaSecp256k1RecoveryMethod2020Context
+		case Iden3StateInfo2023Type:
+			c = Iden3StateInfo2023Context
+		default:
+			return
+		}
+		if !hasContext(c) {
+			ctx = append(ctx, c)
+		}
+	}
+
+	for i := range doc.VerificationMethod {
+		addVMContext(&doc.VerificationMethod[i])
+	}
+	for _, rel := range doc.relationships() {
+		for _, r := range rel.items {
+			if r.Method != nil {
+				addVMContext(r.Method)
+			}
+		}
+	}
+	return ctx
+}
+
+// UnmarshalJSON parses JSON or JSON-LD representation of DID document
+func (doc *DIDDocument) UnmarshalJSON(b []byte) error {
+	var j struct {
+		didDocumentJSON
+		Context    json.RawMessage `json:"@context"`
+		Controller json.RawMessage `json:"controller"`
+	}
+	err := json.Unmarshal(b, &j)
+	if err != nil {
+		return err
+	}
+
+	var ctx []interface{}
+	if len(j.Context) != 0 {
+		ctx, err = unmarshalOneOrMany[interface{}](j.Context)
+		if err != nil {
+			return fmt.Errorf("invalid @context: %w", err)
+		}
+	}
+
+	var controllers []DID
+	if len(j.Controller) != 0 {
+		controllers, err = unmarshalOneOrMany[DID](j.Controller)
+		if err != nil {
+			return fmt.Errorf("invalid controller: %w", err)
+		}
+	}
+
+	*doc = DIDDocument{
+		Context:            ctx,
+		ID:                 j.ID,
+		Controller:         controllers,
+		AlsoKnownAs:        j.AlsoKnownAs,
+		VerificationMethod: j.VerificationMethod,
+		Authentication:     j.Authentication,
+		AssertionMethod:    j.AssertionMethod,
+		Service:            j.Service,
+	}
+	return nil
+}
+
+// unmarshalOneOrMany parses a JSON array or a single JSON value as one
+// element array
+func unmarshalOneOrMany[T any](b json.RawMessage) ([]T, error) {
+	if strings.HasPrefix(strings.TrimSpace(string(b)), "[") {
+		var items []T
+		err := json.Unmarshal(b, &items)
+		return items, err
+	}
+	var item T
+	err := json.Unmarshal(b, &item)
+	if err != nil {
+		return nil, err
+	}
+	return []T{item}, nil
+}
+
+// ResolveReference returns absolute DID URL of the reference. Relative
+// references like #key-1 are resolved against the document DID.
+func (doc *DIDDocument) ResolveReference(ref string) (*DID, error) {
+	if strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "?") ||
+		strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, ";") {
+
+		ref = doc.ID.String() + ref
+	}
+	return ParseDID(ref)
+}
+
+// VerificationMethodByID returns the verification method of the document,
+// listed in VerificationMethod or embedded in verification relationships,
+// by absolute or relative DID URL
+func (doc *DIDDocument) VerificationMethodByID(ref string) (
+	*VerificationMethod, bool) {
+
+	target, err := doc.ResolveReference(ref)
+	if err != nil {
+		return nil, false
+	}
+	match := func(vm *VerificationMethod) bool {
+		u, err := doc.ResolveReference(vm.ID)
+		return err == nil && Equal(*u, *target)
+	}
+
+	for i := range doc.VerificationMethod {
+		if match(&doc.VerificationMethod[i]) {
+			return &doc.VerificationMethod[i], true
+		}
+	}
+	for _, rel := range doc.relationships() {
+		for _, r := range rel.items {
+			if r.Method != nil && match(r.Method) {
+				return r.Method, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ServiceByID returns the service of the document by absolute or relative
+// DID URL
+func (doc *DIDDocument) ServiceByID(ref string) (*Service, bool) {
+	target, err := doc.ResolveReference(ref)
+	if err != nil {
+		return nil, false
+	}
+	for i := range doc.Service {
+		u, err := doc.ResolveReference(doc.Service[i].ID)
+		if err == nil && Equal(*u, *target) {
+			return &doc.Service[i], true
+		}
+	}
+	return nil, false
+}
+
+// Validate checks that the document is consistent:
+//   - ID is a DID and not a DID URL;
+//   - verification methods and services have unique IDs that are fragments
+//     of the document DID;
+//   - verification methods have type specific properties set;
+//   - references in verification relationships to the document DID point to
+//     its verification methods.
+func (doc *DIDDocument) Validate() error {
+	if doc.ID.Method == "" || doc.ID.IsURL() {
+		return fmt.Errorf("%w: id must be a DID: %q", ErrInvalidDIDDocument,
+			doc.ID.String())
+	}
+	if _, err := ParseDID(doc.ID.String()); err != nil {
+		return fmt.Errorf("%w: invalid id: %v", ErrInvalidDIDDocument, err)
+	}
+
+	for _, c := range doc.Controller {
+		if c.Method == "" || c.IsURL() {
+			return fmt.Errorf("%w: controller must be a DID: %q",
+				ErrInvalidDIDDocument, c.String())
+		}
+	}
+
+	ids := map[string]struct{}{}
+	addID := func(ref string) error {
+		u, err := doc.ownFragment(ref)
+		if err != nil {
+			return err
+		}
+		if _, ok := ids[u.String()]; ok {
+			return fmt.Errorf("%w: duplicate id %q", ErrInvalidDIDDocument,
+				ref)
+		}
+		ids[u.String()] = struct{}{}
+		return nil
+	}
+
+	for i := range doc.VerificationMethod {
+		vm := &doc.VerificationMethod[i]
+		if err := addID(vm.ID); err != nil {
+			return err
+		}
+		if err := validateVerificationMethod(vm); err != nil {
+			return err
+		}
+	}
+
+	for _, rel := range doc.relationships() {
+		for _, r := range rel.items {
+			if r.Method != nil {
+				if err := addID(r.Method.ID); err != nil {
+					return err
+				}
+				if err := validateVerificationMethod(r.Method); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, rel := range doc.relationships() {
+		for _, r := range rel.items {
+			if r.Method != nil {
+				continue
+			}
+			if err := doc.validateReference(rel.name, r.Reference); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range doc.Service {
+		s := &doc.Service[i]
+		if err := addID(s.ID); err != nil {
+			return err
+		}
+		if s.Type == "" {
+			return fmt.Errorf("%w: service %q has no type",
+				ErrInvalidDIDDocument, s.ID)
+		}
+		if s.ServiceEndpoint == nil {
+			return fmt.Errorf("%w: service %q has no serviceEndpoint",
+				ErrInvalidDIDDocument, s.ID)
+		}
+	}
+
+	return nil
+}
+
+// ownFragment resolves the reference and checks that it is a fragment of
+// the document DID
+func (doc *DIDDocument) ownFragment(ref string) (*DID, error) {
+	u, err := doc.ResolveReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid DID URL %q: %v",
+			ErrInvalidDIDDocument, ref, err)
+	}
+	if u.Fragment == "" {
+		return nil, fmt.Errorf("%w: DID URL %q has no fragment",
+			ErrInvalidDIDDocument, ref)
+	}
+	if !doc.isOwnDID(u) {
+		return nil, fmt.Errorf("%w: DID URL %q does not belong to %v",
+			ErrInvalidDIDDocument, ref, doc.ID.String())
+	}
+	return u, nil
+}
+
+// validateReference checks that the reference is a DID URL and, if it
+// points to the document DID, that the verification method exists
+func (doc *DIDDocument) validateReference(relName, ref string) error {
+	u, err := doc.ResolveReference(ref)
+	if err != nil {
+		return fmt.Errorf("%w: %v has invalid reference %q: %v",
+			ErrInvalidDIDDocument, relName, ref, err)
+	}
+	if !doc.isOwnDID(u) {
+		return nil
+	}
+	if _, ok := doc.VerificationMethodByID(ref); !ok {
+		return fmt.Errorf("%w: %v references unknown verification method %q",
+			ErrInvalidDIDDocument, relName, ref)
+	}
+	return nil
+}
+
+func (doc *DIDDocument) isOwnDID(u *DID) bool {
+	return Equal(u.Base(), doc.ID.Base())
+}
+
+func methodSpecificID(d *DID) string {
+	if d.ID != "" {
+		return d.ID
+	}
+	return strings.Join(d.IDStrings, ":")
+}
+
+type relationship struct {
+	name  string
+	items []VerificationRelationship
+}
+
+func (doc *DIDDocument) relationships() []relationship {
+	return []relationship{
+		{"authentication", doc.Authentication},
+		{"assertionMethod", doc.AssertionMethod},
+	}
+}
+
+func validateVerificationMethod(vm *VerificationMethod) error {
+	if vm.Type == "" {
+		return fmt.Errorf("%w: verification method %q has no type",
+			ErrInvalidDIDDocument, vm.ID)
+	}
+	if vm.Controller == "" {
+		return fmt.Errorf("%w: verification method %q has no controller",
+			ErrInvalidDIDDocument, vm.ID)
+	}
+	if _, err := ParseDID(vm.Controller); err != nil {
+		return fmt.Errorf("%w: verification method %q has invalid "+
+			"controller: %v", ErrInvalidDIDDocument, vm.ID, err)
+	}
+
+	switch vm.Type {
+	case JSONWebKey2020Type:
+		if len(vm.PublicKeyJwk) == 0 {
+			return fmt.Errorf("%w: %v %q has no publicKeyJwk",
+				ErrInvalidDIDDocument, vm.Type, vm.ID)
+		}
+	case EcdsaSecp256k1RecoveryMethod2020Type:
+		if vm.BlockchainAccountID == "" && vm.EthereumAddress == "" &&
+			len(vm.PublicKeyJwk) == 0 {
+
+			return fmt.Errorf("%w: %v %q has no blockchainAccountId, "+
+				"ethereumAddress or publicKeyJwk", ErrInvalidDIDDocument,
+				vm.Type, vm.ID)
+		}
+	case Iden3StateInfo2023Type:
+		if vm.StateContractAddress == "" {
+			return fmt.Errorf("%w: %v %q has no stateContractAddress",
+				ErrInvalidDIDDocument, vm.Type, vm.ID)
+		}
+	}
+	return nil
+}
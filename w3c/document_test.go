@@ -0,0 +1,244 @@
+package w3c
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testDIDDocument = `{
+  "@context": [
+    "https://www.w3.org/ns/did/v1",
+    "https://schema.iden3.io/core/jsonld/auth.jsonld"
+  ],
+  "id": "did:example:123",
+  "controller": "did:example:456",
+  "alsoKnownAs": ["https://example.com/user"],
+  "verificationMethod": [
+    {
+      "id": "did:example:123#state-info",
+      "type": "Iden3StateInfo2023",
+      "controller": "did:example:123",
+      "stateContractAddress": "80002:0x1a4cC30f2aA0377b0c3bc9848766D90cb4404124",
+      "published": true,
+      "info": {
+        "id": "did:example:123",
+        "state": "1",
+        "replacedByState": "0",
+        "createdAtTimestamp": "1",
+        "replacedAtTimestamp": "0",
+        "createdAtBlock": "1",
+        "replacedAtBlock": "0"
+      }
+    },
+    {
+      "id": "#key-1",
+      "type": "JsonWebKey2020",
+      "controller": "did:example:123",
+      "publicKeyJwk": {"kty": "EC", "crv": "secp256k1", "x": "a", "y": "b"}
+    }
+  ],
+  "authentication": [
+    "#state-info",
+    {
+      "id": "did:example:123#recovery",
+      "type": "EcdsaSecp256k1RecoveryMethod2020",
+      "controller": "did:example:123",
+      "blockchainAccountId": "eip155:1:0xA51c1fc2f0D1a1b8494Ed1FE312d7C3a78Ed91C0"
+    }
+  ],
+  "assertionMethod": ["did:example:123#key-1", "did:other:1#key"],
+  "service": [
+    {
+      "id": "#push",
+      "type": "push-notification",
+      "serviceEndpoint": [{"id": "1", "url": "https://push.example.com"}]
+    }
+  ]
+}`
+
+func TestDIDDocument_JSON(t *testing.T) {
+	var doc DIDDocument
+	err := json.Unmarshal([]byte(testDIDDocument), &doc)
+	assert(t, nil, err)
+
+	assert(t, []interface{}{DIDContextV1, Iden3StateInfo2023Context},
+		doc.Context)
+	assert(t, "did:example:123", doc.ID.String())
+	assert(t, 1, len(doc.Controller))
+	assert(t, "did:example:456", doc.Controller[0].String())
+	assert(t, 2, len(doc.VerificationMethod))
+	assert(t, true, *doc.VerificationMethod[0].Published)
+	assert(t, "1", doc.VerificationMethod[0].Info.State)
+	assert(t, "#state-info", doc.Authentication[0].Reference)
+	assert(t, EcdsaSecp256k1RecoveryMethod2020Type,
+		doc.Authentication[1].Method.Type)
+	assert(t, nil, doc.Validate())
+
+	b, err := json.Marshal(doc)
+	assert(t, nil, err)
+	var doc2 DIDDocument
+	err = json.Unmarshal(b, &doc2)
+	assert(t, nil, err)
+	assert(t, doc, doc2)
+
+	// single context and controller are written as strings
+	doc.Context = []interface{}{DIDContextV1}
+	doc.Controller = doc.Controller[:1]
+	b, err = json.Marshal(doc)
+	assert(t, nil, err)
+	assert(t, true, strings.Contains(string(b),
+		`"@context":"https://www.w3.org/ns/did/v1"`))
+	assert(t, true, strings.Contains(string(b),
+		`"controller":"did:example:456"`))
+
+	// no context in plain JSON
+	doc.Context = nil
+	b, err = json.Marshal(doc)
+	assert(t, nil, err)
+	assert(t, false, strings.Contains(string(b), "@context"))
+}
+
+func TestDIDDocument_MarshalJSONLD(t *testing.T) {
+	var doc DIDDocument
+	err := json.Unmarshal([]byte(testDIDDocument), &doc)
+	assert(t, nil, err)
+	doc.Context = []interface{}{"https://example.com/ctx", DIDContextV1}
+
+	b, err := doc.MarshalJSONLD()
+	assert(t, nil, err)
+
+	var out struct {
+		Context []interface{} `json:"@context"`
+	}
+	err = json.Unmarshal(b, &out)
+	assert(t, nil, err)
+	assert(t, []interface{}{
+		DIDContextV1,
+		"https://example.com/ctx",
+		Iden3StateInfo2023Context,
+		JSONWebKey2020Context,
+		EcdsaSecp256k1RecoveryMethod2020Context,
+	}, out.Context)
+
+	// Context of the document is not modified
+	assert(t, []interface{}{"https://example.com/ctx", DIDContextV1},
+		doc.Context)
+}
+
+func TestDIDDocument_Lookup(t *testing.T) {
+	var doc DIDDocument
+	err := json.Unmarshal([]byte(testDIDDocument), &doc)
+	assert(t, nil, err)
+
+	vm, ok := doc.VerificationMethodByID("#key-1")
+	assert(t, true, ok)
+	assert(t, JSONWebKey2020Type, vm.Type)
+
+	vm, ok = doc.VerificationMethodByID("did:example:123#recovery")
+	assert(t, true, ok)
+	assert(t, EcdsaSecp256k1RecoveryMethod2020Type, vm.Type)
+
+	// DID URLs are compared by DID equivalence rules
+	vm, ok = doc.VerificationMethodByID("#key%2d1")
+	assert(t, true, ok)
+	assert(t, JSONWebKey2020Type, vm.Type)
+
+	_, ok = doc.VerificationMethodByID("#unknown")
+	assert(t, false, ok)
+
+	s, ok := doc.ServiceByID("did:example:123#push")
+	assert(t, true, ok)
+	assert(t, "push-notification", s.Type)
+
+	u, err := doc.ResolveReference("#key-1")
+	assert(t, nil, err)
+	assert(t, "did:example:123#key-1", u.String())
+}
+
+func TestDIDDocument_Validate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(doc *DIDDocument)
+		errMsg string
+	}{
+		{
+			name:   "DID URL as id",
+			modify: func(doc *DIDDocument) { doc.ID.Fragment = "x" },
+			errMsg: `invalid DID document: id must be a DID: "did:example:123#x"`,
+		},
+		{
+			name: "foreign verification method",
+			modify: func(doc *DIDDocument) {
+				doc.VerificationMethod[1].ID = "did:example:456#key-1"
+			},
+			errMsg: `invalid DID document: DID URL "did:example:456#key-1" does not belong to did:example:123`,
+		},
+		{
+			name: "verification method without fragment",
+			modify: func(doc *DIDDocument) {
+				doc.VerificationMethod[1].ID = "did:example:123"
+			},
+			errMsg: `invalid DID document: DID URL "did:example:123" has no fragment`,
+		},
+		{
+			name: "duplicate id",
+			modify: func(doc *DIDDocument) {
+				doc.Service[0].ID = "#key-1"
+			},
+			errMsg: `invalid DID document: duplicate id "#key-1"`,
+		},
+		{
+			name: "unknown reference",
+			modify: func(doc *DIDDocument) {
+				doc.AssertionMethod[0].Reference = "#key-2"
+			},
+			errMsg: `invalid DID document: assertionMethod references unknown verification method "#key-2"`,
+		},
+		{
+			name: "missing jwk",
+			modify: func(doc *DIDDocument) {
+				doc.VerificationMethod[1].PublicKeyJwk = nil
+			},
+			errMsg: `invalid DID document: JsonWebKey2020 "#key-1" has no publicKeyJwk`,
+		},
+		{
+			name: "missing state contract",
+			modify: func(doc *DIDDocument) {
+				doc.VerificationMethod[0].StateContractAddress = ""
+			},
+			errMsg: `invalid DID document: Iden3StateInfo2023 "did:example:123#state-info" has no stateContractAddress`,
+		},
+		{
+			name: "missing account of embedded method",
+			modify: func(doc *DIDDocument) {
+				doc.Authentication[1].Method.BlockchainAccountID = ""
+			},
+			errMsg: `invalid DID document: EcdsaSecp256k1RecoveryMethod2020 "did:example:123#recovery" has no blockchainAccountId, ethereumAddress or publicKeyJwk`,
+		},
+		{
+			name: "service without endpoint",
+			modify: func(doc *DIDDocument) {
+				doc.Service[0].ServiceEndpoint = nil
+			},
+			errMsg: `invalid DID document: service "#push" has no serviceEndpoint`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc DIDDocument
+			err := json.Unmarshal([]byte(testDIDDocument), &doc)
+			assert(t, nil, err)
+
+			tc.modify(&doc)
+			err = doc.Validate()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			assert(t, true, errors.Is(err, ErrInvalidDIDDocument))
+			assert(t, tc.errMsg, err.Error())
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package w3c
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 func (did *DID) UnmarshalJSON(bytes []byte) error {
 	var didStr string
@@ -20,3 +23,23 @@ func (did *DID) UnmarshalJSON(bytes []byte) error {
 func (did DID) MarshalJSON() ([]byte, error) {
 	return json.Marshal(did.String())
 }
+
+// MarshalText returns DID string. It returns error for DID that can't be
+// encoded, e.g. without method or method-specific-id.
+func (did DID) MarshalText() ([]byte, error) {
+	s := did.String()
+	if s == "" {
+		return nil, fmt.Errorf("can't marshal invalid DID: %+v", did)
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses DID string by ParseDID
+func (did *DID) UnmarshalText(text []byte) error {
+	did2, err := ParseDID(string(text))
+	if err != nil {
+		return err
+	}
+	*did = *did2
+	return nil
+}
@@ -0,0 +1,40 @@
+package w3c
+
+import (
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = DID{}
+	_ encoding.TextUnmarshaler = (*DID)(nil)
+)
+
+func TestDID_Text(t *testing.T) {
+	did, err := ParseDID("did:example:123:456#key-1")
+	assert(t, nil, err)
+
+	text, err := did.MarshalText()
+	assert(t, nil, err)
+	assert(t, "did:example:123:456#key-1", string(text))
+
+	var did2 DID
+	err = did2.UnmarshalText(text)
+	assert(t, nil, err)
+	assert(t, *did, did2)
+
+	_, err = (&DID{Method: "example"}).MarshalText()
+	assert(t, false, err == nil)
+
+	err = did2.UnmarshalText([]byte("did:ex"))
+	assert(t, "input length is less than 7", err.Error())
+	assert(t, *did, did2)
+
+	// JSON encoding is not changed by text marshalling
+	b, err := json.Marshal(struct {
+		DID DID `json:"did"`
+	}{*did})
+	assert(t, nil, err)
+	assert(t, `{"did":"did:example:123:456#key-1"}`, string(b))
+}
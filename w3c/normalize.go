@@ -0,0 +1,117 @@
+package w3c
+
+import "strings"
+
+// Base returns the DID of DID URL, i.e. the DID without params, path, query
+// and fragment
+func (d *DID) Base() DID {
+	base := DID{Method: d.Method, ID: d.ID}
+	if len(d.IDStrings) != 0 {
+		base.IDStrings = append([]string(nil), d.IDStrings...)
+	}
+	if base.ID == "" {
+		base.ID = strings.Join(base.IDStrings, ":")
+	} else if len(base.IDStrings) == 0 {
+		base.IDStrings = strings.Split(base.ID, ":")
+	}
+	return base
+}
+
+// Normalize returns the normal form of DID URL as used for DID equivalence
+// https://www.w3.org/TR/did-core/#did-syntax
+//
+// ID and IDStrings, Path and PathSegments are set consistently. Percent-encoded
+// unreserved characters (ALPHA / DIGIT / "-" / "." / "_" / "~") are decoded if
+// they are allowed in the component and hex digits of other percent-encoded
+// characters are uppercased (RFC 3986 section 6.2.2).
+func (d *DID) Normalize() DID {
+	n := d.Base()
+	for i := range n.IDStrings {
+		n.IDStrings[i] = normalizePercentEncoding(n.IDStrings[i],
+			isNotValidIDChar)
+	}
+	n.ID = strings.Join(n.IDStrings, ":")
+
+	for _, p := range d.Params {
+		n.Params = append(n.Params, Param{
+			Name:  normalizePercentEncoding(p.Name, isNotValidParamChar),
+			Value: normalizePercentEncoding(p.Value, isNotValidParamChar),
+		})
+	}
+
+	segments := d.PathSegments
+	if len(segments) == 0 && d.Path != "" {
+		segments = strings.Split(d.Path, "/")
+	}
+	for _, s := range segments {
+		n.PathSegments = append(n.PathSegments,
+			normalizePercentEncoding(s, isNotValidPathChar))
+	}
+	n.Path = strings.Join(n.PathSegments, "/")
+
+	n.Query = normalizePercentEncoding(d.Query, isNotValidQueryOrFragmentChar)
+	n.Fragment = normalizePercentEncoding(d.Fragment,
+		isNotValidQueryOrFragmentChar)
+	return n
+}
+
+// Equal returns true if DIDs or DID URLs are equivalent by DID Core rules,
+// i.e. their normal forms are the same. DID URLs are not equal to their base
+// DIDs, use Base to compare the DIDs of DID URLs:
+//
+//	Equal(a.Base(), b.Base())
+func Equal(a, b DID) bool {
+	na, nb := a.Normalize(), b.Normalize()
+	return na.String() == nb.String()
+}
+
+// normalizePercentEncoding decodes percent-encoded unreserved characters
+// allowed in the component by isNotAllowed and uppercases hex digits of other
+// percent-encoded characters
+func normalizePercentEncoding(s string, isNotAllowed func(byte) bool) string {
+	if strings.IndexByte(s, '%') < 0 {
+		return s
+	}
+
+	const upperHex = "0123456789ABCDEF"
+	var buf strings.Builder
+	buf.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) ||
+			isNotHexDigit(s[i+1]) || isNotHexDigit(s[i+2]) {
+
+			buf.WriteByte(s[i])
+			continue
+		}
+		char := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(char) && !isNotAllowed(char) {
+			buf.WriteByte(char)
+		} else {
+			buf.WriteByte('%')
+			buf.WriteByte(upperHex[char>>4])
+			buf.WriteByte(upperHex[char&0x0F])
+		}
+		i += 2
+	}
+	return buf.String()
+}
+
+// isUnreserved returns true if a byte is unreserved character
+//
+//	unreserved = ALPHA / DIGIT / "-" / "." / "_" / "~"
+func isUnreserved(char byte) bool {
+	return !isNotAlpha(char) || !isNotDigit(char) ||
+		char == '-' || char == '.' || char == '_' || char == '~'
+}
+
+// unhex returns the value of hex digit
+func unhex(char byte) byte {
+	switch {
+	case char >= 'a':
+		return char - 'a' + 10
+	case char >= 'A':
+		return char - 'A' + 10
+	default:
+		return char - '0'
+	}
+}
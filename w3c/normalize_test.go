@@ -0,0 +1,71 @@
+package w3c
+
+import "testing"
+
+func TestDID_Base(t *testing.T) {
+	did, err := ParseDID("did:example:123:456;p=1/a/b?q=1#key-1")
+	assert(t, nil, err)
+	base := did.Base()
+	assert(t, DID{Method: "example", ID: "123:456",
+		IDStrings: []string{"123", "456"}}, base)
+	assert(t, "did:example:123:456", base.String())
+	assert(t, false, base.IsURL())
+
+	base = (&DID{Method: "example", IDStrings: []string{"1", "2"},
+		Fragment: "x"}).Base()
+	assert(t, "1:2", base.ID)
+}
+
+func TestDID_Normalize(t *testing.T) {
+	did, err := ParseDID(
+		"did:example:123;p=%2d%7e/a/%7Eb%2f?q=%41%2b%3d#%6b%65y-%c3%a9")
+	assert(t, nil, err)
+	n := did.Normalize()
+	assert(t, "did:example:123;p=-%7E/a/~b%2F?q=A%2B%3D#key-%C3%A9",
+		n.String())
+	assert(t, "a/~b%2F", n.Path)
+	assert(t, []string{"a", "~b%2F"}, n.PathSegments)
+
+	// the DID is not changed
+	assert(t, "did:example:123;p=%2d%7e/a/%7Eb%2f?q=%41%2b%3d#%6b%65y-%c3%a9",
+		did.String())
+
+	// normal form is stable
+	n2 := n.Normalize()
+	assert(t, n.String(), n2.String())
+
+	// Path is used if PathSegments are not set
+	n = (&DID{Method: "example", ID: "123", Path: "%61/b"}).Normalize()
+	assert(t, []string{"a", "b"}, n.PathSegments)
+}
+
+func TestEqual(t *testing.T) {
+	testCases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"did:example:123", "did:example:123", true},
+		{"did:example:%31%32%33:a%2fb", "did:example:123:a%2Fb", true},
+		{"did:example:123#key%2d1", "did:example:123#key-1", true},
+		{"did:example:123?a=%2f", "did:example:123?a=%2F", true},
+		{"did:example:123/%7e", "did:example:123/~", true},
+		{"did:example:123", "did:example:123#key-1", false},
+		{"did:example:123", "did:example:1234", false},
+		{"did:example:123?a=%2F", "did:example:123?a=/", false},
+	}
+	for _, tc := range testCases {
+		a, err := ParseDID(tc.a)
+		assert(t, nil, err)
+		b, err := ParseDID(tc.b)
+		assert(t, nil, err)
+		assert(t, tc.equal, Equal(*a, *b), "%v == %v", tc.a, tc.b)
+	}
+
+	a, err := ParseDID("did:example:123#key-1")
+	assert(t, nil, err)
+	b, err := ParseDID("did:example:123?service=x")
+	assert(t, nil, err)
+	assert(t, true, Equal(a.Base(), b.Base()))
+	assert(t, true, Equal(*a, DID{Method: "example",
+		IDStrings: []string{"123"}, Fragment: "key-1"}))
+}
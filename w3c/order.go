@@ -0,0 +1,37 @@
+package w3c
+
+import (
+	"sort"
+	"strings"
+)
+
+// Compare returns an integer comparing two DIDs lexicographically by their
+// string encodings as returned by String. The result is 0 if d == d2, -1 if
+// d < d2 and +1 if d > d2. So DIDs are ordered by method first, then by
+// method-specific-id and then by DID URL components.
+func (d *DID) Compare(d2 *DID) int {
+	return strings.Compare(d.String(), d2.String())
+}
+
+// SortDIDs sorts DIDs in place in the order of DID.Compare
+func SortDIDs(dids []DID) {
+	keys := make([]string, len(dids))
+	for i := range dids {
+		keys[i] = dids[i].String()
+	}
+	sort.Sort(&didsByString{dids: dids, keys: keys})
+}
+
+type didsByString struct {
+	dids []DID
+	keys []string
+}
+
+func (s *didsByString) Len() int { return len(s.dids) }
+
+func (s *didsByString) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+
+func (s *didsByString) Swap(i, j int) {
+	s.dids[i], s.dids[j] = s.dids[j], s.dids[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
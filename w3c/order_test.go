@@ -0,0 +1,37 @@
+package w3c
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	a := &DID{Method: "example", ID: "123"}
+	b := &DID{Method: "example", ID: "123", Fragment: "key-1"}
+	c := &DID{Method: "iden3", ID: "abc"}
+
+	assert(t, 0, a.Compare(&DID{Method: "example", ID: "123"}))
+	assert(t, -1, a.Compare(b))
+	assert(t, 1, b.Compare(a))
+	assert(t, -1, b.Compare(c))
+}
+
+func TestSortDIDs(t *testing.T) {
+	dids := []DID{
+		{Method: "iden3", ID: "abc"},
+		{Method: "example", ID: "123", Fragment: "key-1"},
+		{Method: "example", ID: "123"},
+		{Method: "example", ID: "012"},
+	}
+	SortDIDs(dids)
+
+	var got []string
+	for i := range dids {
+		got = append(got, dids[i].String())
+	}
+	assert(t, []string{
+		"did:example:012",
+		"did:example:123",
+		"did:example:123#key-1",
+		"did:iden3:abc",
+	}, got)
+}
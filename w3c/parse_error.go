@@ -0,0 +1,156 @@
+package w3c
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseErrorCategory is a part of DID syntax that failed to parse
+type ParseErrorCategory string
+
+// Categories of ParseError returned by ParseDID, ParseDIDRef and Validate.
+// DID methods may define their own categories.
+const (
+	ParseErrorScheme   ParseErrorCategory = "scheme"
+	ParseErrorMethod   ParseErrorCategory = "method"
+	ParseErrorIDChar   ParseErrorCategory = "idchar"
+	ParseErrorParam    ParseErrorCategory = "param"
+	ParseErrorPath     ParseErrorCategory = "path"
+	ParseErrorQuery    ParseErrorCategory = "query"
+	ParseErrorFragment ParseErrorCategory = "fragment"
+)
+
+// ParseError is an error of parsing DID or DID URL at the byte offset of the
+// input. Use errors.As to get it from errors returned by ParseDID.
+type ParseError struct {
+	// Input is the parsed string
+	Input string
+	// Offset is the byte offset of the error in Input. It equals len(Input)
+	// if input ends unexpectedly.
+	Offset int
+	// Rule is the grammar rule that failed, may be empty
+	Rule string
+	// Category is the part of DID syntax that failed
+	Category ParseErrorCategory
+	// Msg describes the error
+	Msg string
+	// Err is the cause of the error, may be nil
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Msg
+	case e.Msg == "":
+		return e.Err.Error()
+	default:
+		return e.Err.Error() + ": " + e.Msg
+	}
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Pretty returns multiline description of the error with the caret under
+// the failed position of the input, e.g.
+//
+//	byte is not ALPHA OR DIGIT OR '.' OR '-' (idchar at offset 14)
+//	did:example:12^4
+//	              ^
+//	rule: idchar = ALPHA / DIGIT / "." / "-" / pct-encoded
+func (e *ParseError) Pretty() string {
+	offset := e.Offset
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(e.Input) {
+		offset = len(e.Input)
+	}
+
+	var buf strings.Builder
+	buf.WriteString(e.Error())
+	if e.Category != "" {
+		fmt.Fprintf(&buf, " (%v at offset %v)", e.Category, e.Offset)
+	} else {
+		fmt.Fprintf(&buf, " (at offset %v)", e.Offset)
+	}
+	buf.WriteByte('\n')
+	buf.WriteString(e.Input)
+	buf.WriteByte('\n')
+	buf.WriteString(strings.Repeat(" ",
+		utf8.RuneCountInString(e.Input[:offset])))
+	buf.WriteByte('^')
+	if e.Rule != "" {
+		buf.WriteString("\nrule: ")
+		buf.WriteString(e.Rule)
+	}
+	return buf.String()
+}
+
+// grammarRule is the rule of DID syntax checked by the parser
+type grammarRule string
+
+// rules of DID syntax reported in ParseError
+const (
+	ruleDID        grammarRule = `did = "did:" method ":" specific-idstring`
+	ruleMethod     grammarRule = `method = 1*methodchar`
+	ruleMethodChar grammarRule = `methodchar = %x61-7A / DIGIT`
+	ruleIDString   grammarRule = `idstring = 1*idchar`
+	ruleIDChar     grammarRule = `idchar = ALPHA / DIGIT / "." / "-" / pct-encoded`
+	ruleParamName  grammarRule = `param-name = 1*param-char`
+	ruleParamChar  grammarRule = `param-char = ALPHA / DIGIT / "." / "-" / "_" / ":" / pct-encoded`
+	rulePctEncoded grammarRule = `pct-encoded = "%" HEXDIG HEXDIG`
+	rulePath       grammarRule = `did-path = segment-nz *( "/" segment )`
+	rulePChar      grammarRule = `pchar = unreserved / pct-encoded / sub-delims / ":" / "@"`
+	ruleQuery      grammarRule = `did-query = *( pchar / "/" / "?" )`
+	ruleFragment   grammarRule = `did-fragment = *( pchar / "/" / "?" )`
+)
+
+// newParseError returns ParseError of the rule at the offset of the input.
+// The category is the component of the input at the offset.
+func newParseError(input string, offset int, rule grammarRule, format string,
+	args ...interface{}) *ParseError {
+
+	return &ParseError{
+		Input:    input,
+		Offset:   offset,
+		Rule:     string(rule),
+		Category: parseErrorCategory(input, offset),
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
+
+// parseErrorCategory returns the component of DID URL at the offset
+func parseErrorCategory(input string, offset int) ParseErrorCategory {
+	if len(input) < 7 || offset < len("did:") {
+		return ParseErrorScheme
+	}
+	methodLen := strings.IndexByte(input[len("did:"):], ':')
+	if methodLen < 0 || offset <= len("did:")+methodLen {
+		return ParseErrorMethod
+	}
+
+	if offset > len(input) {
+		offset = len(input)
+	}
+	category := ParseErrorIDChar
+	for i := len("did:") + methodLen + 1; i < offset; i++ {
+		switch input[i] {
+		case '#':
+			return ParseErrorFragment
+		case '?':
+			category = ParseErrorQuery
+		case '/':
+			if category == ParseErrorIDChar || category == ParseErrorParam {
+				category = ParseErrorPath
+			}
+		case ';':
+			if category == ParseErrorIDChar {
+				category = ParseErrorParam
+			}
+		}
+	}
+	return category
+}
@@ -0,0 +1,156 @@
+package w3c
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		input    string
+		offset   int
+		category ParseErrorCategory
+		rule     string
+		msg      string
+	}{
+		{
+			input:    "did:a",
+			offset:   5,
+			category: ParseErrorScheme,
+			rule:     `did = "did:" method ":" specific-idstring`,
+			msg:      "input length is less than 7",
+		},
+		{
+			input:    "urn:a:123",
+			offset:   3,
+			category: ParseErrorScheme,
+			rule:     `did = "did:" method ":" specific-idstring`,
+			msg:      "input does not begin with 'did:' prefix",
+		},
+		{
+			input:    "did:aBc:123",
+			offset:   5,
+			category: ParseErrorMethod,
+			rule:     `methodchar = %x61-7A / DIGIT`,
+			msg:      "character is not a-z OR 0-9",
+		},
+		{
+			input:    "did:abc123",
+			offset:   10,
+			category: ParseErrorMethod,
+			rule:     `did = "did:" method ":" specific-idstring`,
+			msg:      "input does not have a second `:` marking end of method name",
+		},
+		{
+			input:    "did:example:12^4",
+			offset:   14,
+			category: ParseErrorIDChar,
+			rule:     `idchar = ALPHA / DIGIT / "." / "-" / pct-encoded`,
+			msg:      "byte is not ALPHA OR DIGIT OR '.' OR '-'",
+		},
+		{
+			input:    "did:example:12%4",
+			offset:   14,
+			category: ParseErrorIDChar,
+			rule:     `pct-encoded = "%" HEXDIG HEXDIG`,
+			msg:      "% is not followed by 2 hex digits",
+		},
+		{
+			input:    "did:a:123:;p",
+			offset:   10,
+			category: ParseErrorIDChar,
+			rule:     `idstring = 1*idchar`,
+			msg:      "idstring must be atleast one char long",
+		},
+		{
+			input:    "did:a:123;p=^",
+			offset:   12,
+			category: ParseErrorParam,
+			rule:     `param-char = ALPHA / DIGIT / "." / "-" / "_" / ":" / pct-encoded`,
+			msg:      "character is not allowed in param - ^",
+		},
+		{
+			input:    "did:a:123;p/a%2",
+			offset:   13,
+			category: ParseErrorPath,
+			rule:     `pct-encoded = "%" HEXDIG HEXDIG`,
+			msg:      "% is not followed by 2 hex digits",
+		},
+		{
+			input:    "did:a:123//a",
+			offset:   10,
+			category: ParseErrorPath,
+			rule:     `did-path = segment-nz *( "/" segment )`,
+			msg:      "first path segment must have atleast one character",
+		},
+		{
+			input:    "did:a:123/a?b/c^",
+			offset:   15,
+			category: ParseErrorQuery,
+			rule:     `did-query = *( pchar / "/" / "?" )`,
+			msg:      "character is not allowed in query - ^",
+		},
+		{
+			input:    "did:a:123?a#b/?#",
+			offset:   15,
+			category: ParseErrorFragment,
+			rule:     `did-fragment = *( pchar / "/" / "?" )`,
+			msg:      "character is not allowed in fragment - #",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			want := &ParseError{
+				Input:    tc.input,
+				Offset:   tc.offset,
+				Rule:     tc.rule,
+				Category: tc.category,
+				Msg:      tc.msg,
+			}
+
+			_, err := ParseDID(tc.input)
+			var parseErr *ParseError
+			assert(t, true, errors.As(err, &parseErr))
+			assert(t, want, parseErr)
+			assert(t, tc.msg, err.Error())
+
+			_, err = ParseDIDRef(tc.input)
+			assert(t, true, errors.As(err, &parseErr))
+			assert(t, want, parseErr)
+		})
+	}
+}
+
+func TestParseError_Wrapped(t *testing.T) {
+	cause := errors.New("incorrect DID")
+	err := &ParseError{Input: "did:a:123", Offset: 6, Msg: "bad ID",
+		Err: cause}
+	assert(t, "incorrect DID: bad ID", err.Error())
+	assert(t, true, errors.Is(err, cause))
+
+	err.Msg = ""
+	assert(t, "incorrect DID", err.Error())
+}
+
+func TestParseError_Pretty(t *testing.T) {
+	_, err := ParseDID("did:example:12^4")
+	var parseErr *ParseError
+	assert(t, true, errors.As(err, &parseErr))
+	assert(t, `byte is not ALPHA OR DIGIT OR '.' OR '-' (idchar at offset 14)
+did:example:12^4
+              ^
+rule: idchar = ALPHA / DIGIT / "." / "-" / pct-encoded`, parseErr.Pretty())
+
+	// caret after the end of input
+	_, err = ParseDID("did:a:123/")
+	assert(t, true, errors.As(err, &parseErr))
+	assert(t, `first path segment must have atleast one character (path at offset 10)
+did:a:123/
+          ^
+rule: did-path = segment-nz *( "/" segment )`, parseErr.Pretty())
+
+	// caret is aligned by characters
+	err = &ParseError{Input: "did:é:1", Offset: 6, Msg: "not a-z"}
+	assert(t, "not a-z (at offset 6)\ndid:é:1\n     ^",
+		err.(*ParseError).Pretty())
+}
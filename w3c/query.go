@@ -0,0 +1,232 @@
+package w3c
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DID parameters of DID URL query defined by DID Core
+// https://www.w3.org/TR/did-core/#did-parameters
+const (
+	QueryParamService     = "service"
+	QueryParamRelativeRef = "relativeRef"
+	QueryParamVersionID   = "versionId"
+	QueryParamVersionTime = "versionTime"
+	QueryParamHashLink    = "hl"
+)
+
+// QueryParam is a decoded name=value pair of DID URL query
+type QueryParam struct {
+	Name  string
+	Value string
+}
+
+// QueryParams is an ordered multimap of DID URL query parameters. Names and
+// values are decoded, they are percent-encoded back by Encode.
+type QueryParams []QueryParam
+
+// ParseQueryParams parses and decodes query of DID URL. Pairs are separated
+// by "&", "+" is decoded as space as in url.ParseQuery.
+func ParseQueryParams(query string) (QueryParams, error) {
+	var q QueryParams
+	for query != "" {
+		var pair string
+		if i := strings.IndexByte(query, '&'); i >= 0 {
+			pair, query = query[:i], query[i+1:]
+		} else {
+			pair, query = query, ""
+		}
+		if pair == "" {
+			continue
+		}
+
+		name, value := pair, ""
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			name, value = pair[:i], pair[i+1:]
+		}
+		var err error
+		name, err = url.QueryUnescape(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: query: %v", ErrInvalidDIDURL, err)
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: query: %v", ErrInvalidDIDURL, err)
+		}
+		q = append(q, QueryParam{Name: name, Value: value})
+	}
+	return q, nil
+}
+
+// Get returns the first value of the parameter or empty string
+func (q QueryParams) Get(name string) string {
+	for _, p := range q {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// Values returns all values of the parameter in the order of the query
+func (q QueryParams) Values(name string) []string {
+	var values []string
+	for _, p := range q {
+		if p.Name == name {
+			values = append(values, p.Value)
+		}
+	}
+	return values
+}
+
+// Has returns true if the query has the parameter
+func (q QueryParams) Has(name string) bool {
+	for _, p := range q {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Add appends the parameter to the query
+func (q *QueryParams) Add(name, value string) {
+	*q = append(*q, QueryParam{Name: name, Value: value})
+}
+
+// Set replaces the first value of the parameter and removes other values.
+// The parameter is appended if the query has no such parameter.
+func (q *QueryParams) Set(name, value string) {
+	out := (*q)[:0]
+	found := false
+	for _, p := range *q {
+		if p.Name != name {
+			out = append(out, p)
+		} else if !found {
+			found = true
+			out = append(out, QueryParam{Name: name, Value: value})
+		}
+	}
+	if !found {
+		out = append(out, QueryParam{Name: name, Value: value})
+	}
+	*q = out
+}
+
+// Del removes all values of the parameter
+func (q *QueryParams) Del(name string) {
+	out := (*q)[:0]
+	for _, p := range *q {
+		if p.Name != name {
+			out = append(out, p)
+		}
+	}
+	*q = out
+}
+
+// Encode returns percent-encoded query in the order of parameters. The result
+// is valid DID URL query and is decoded by ParseQueryParams and
+// url.ParseQuery to the same parameters.
+func (q QueryParams) Encode() string {
+	var buf strings.Builder
+	for i, p := range q {
+		if i > 0 {
+			buf.WriteByte('&')
+		}
+		buf.WriteString(percentEncode(p.Name, isNotValidQueryParamChar))
+		buf.WriteByte('=')
+		buf.WriteString(percentEncode(p.Value, isNotValidQueryParamChar))
+	}
+	return buf.String()
+}
+
+// Service returns the value of service parameter
+func (q QueryParams) Service() string {
+	return q.Get(QueryParamService)
+}
+
+// RelativeRef returns the value of relativeRef parameter
+func (q QueryParams) RelativeRef() string {
+	return q.Get(QueryParamRelativeRef)
+}
+
+// VersionID returns the value of versionId parameter
+func (q QueryParams) VersionID() string {
+	return q.Get(QueryParamVersionID)
+}
+
+// HashLink returns the value of hl parameter
+func (q QueryParams) HashLink() string {
+	return q.Get(QueryParamHashLink)
+}
+
+// VersionTime returns the value of versionTime parameter. It returns zero
+// time if there is no such parameter.
+func (q QueryParams) VersionTime() (time.Time, error) {
+	v := q.Get(QueryParamVersionTime)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %v: %v", ErrInvalidDIDURL,
+			QueryParamVersionTime, err)
+	}
+	return t, nil
+}
+
+// SetVersionTime sets versionTime parameter formatted by FormatTime
+func (q *QueryParams) SetVersionTime(t time.Time) {
+	q.Set(QueryParamVersionTime, FormatTime(t))
+}
+
+// Validate checks values of DID Core parameters: every parameter is used
+// at most once, versionTime is RFC 3339 time, relativeRef is a relative URI
+// reference used with service and other parameters are not empty.
+func (q QueryParams) Validate() error {
+	standard := []string{QueryParamService, QueryParamRelativeRef,
+		QueryParamVersionID, QueryParamVersionTime, QueryParamHashLink}
+	for _, name := range standard {
+		values := q.Values(name)
+		if len(values) > 1 {
+			return fmt.Errorf("%w: %v is used more than once",
+				ErrInvalidDIDURL, name)
+		}
+		if len(values) == 1 && values[0] == "" {
+			return fmt.Errorf("%w: %v is empty", ErrInvalidDIDURL, name)
+		}
+	}
+
+	if _, err := q.VersionTime(); err != nil {
+		return err
+	}
+
+	if ref := q.RelativeRef(); ref != "" {
+		if q.Service() == "" {
+			return fmt.Errorf("%w: %v is used without %v", ErrInvalidDIDURL,
+				QueryParamRelativeRef, QueryParamService)
+		}
+		u, err := url.Parse(ref)
+		if err != nil {
+			return fmt.Errorf("%w: invalid %v: %v", ErrInvalidDIDURL,
+				QueryParamRelativeRef, err)
+		}
+		if u.IsAbs() {
+			return fmt.Errorf("%w: %v is not relative: %q", ErrInvalidDIDURL,
+				QueryParamRelativeRef, ref)
+		}
+	}
+	return nil
+}
+
+// QueryParams returns decoded parameters of the DID URL query
+func (d *DID) QueryParams() (QueryParams, error) {
+	return ParseQueryParams(d.Query)
+}
+
+// SetQueryParams replaces the query of the DID URL with encoded parameters
+func (d *DID) SetQueryParams(q QueryParams) {
+	d.Query = q.Encode()
+}
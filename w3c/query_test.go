@@ -0,0 +1,118 @@
+package w3c
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseQueryParams(t *testing.T) {
+	q, err := ParseQueryParams(
+		"service=agent&relativeRef=%2Fa%3Fb%3D1&a=1&&b&a=2+3&versionTime=2023-11-14T22:15:00Z")
+	assert(t, nil, err)
+	assert(t, QueryParams{
+		{Name: "service", Value: "agent"},
+		{Name: "relativeRef", Value: "/a?b=1"},
+		{Name: "a", Value: "1"},
+		{Name: "b", Value: ""},
+		{Name: "a", Value: "2 3"},
+		{Name: "versionTime", Value: "2023-11-14T22:15:00Z"},
+	}, q)
+	assert(t, nil, q.Validate())
+
+	assert(t, "agent", q.Service())
+	assert(t, "/a?b=1", q.RelativeRef())
+	assert(t, "", q.VersionID())
+	assert(t, "", q.HashLink())
+	assert(t, "1", q.Get("a"))
+	assert(t, []string{"1", "2 3"}, q.Values("a"))
+	assert(t, true, q.Has("b"))
+	assert(t, false, q.Has("c"))
+	vt, err := q.VersionTime()
+	assert(t, nil, err)
+	assert(t, time.Date(2023, 11, 14, 22, 15, 0, 0, time.UTC), vt)
+
+	q, err = ParseQueryParams("")
+	assert(t, nil, err)
+	assert(t, QueryParams(nil), q)
+	vt, err = q.VersionTime()
+	assert(t, nil, err)
+	assert(t, true, vt.IsZero())
+
+	_, err = ParseQueryParams("a=%2")
+	assert(t, true, errors.Is(err, ErrInvalidDIDURL))
+}
+
+func TestQueryParams_Setters(t *testing.T) {
+	var q QueryParams
+	q.Add("a", "1")
+	q.Add("b", "x")
+	q.Add("a", "2")
+	q.Set("a", "3")
+	assert(t, QueryParams{{"a", "3"}, {"b", "x"}}, q)
+	q.Set("c", "a&b=c+d;e f/?:@%")
+	q.SetVersionTime(time.Date(2023, 11, 14, 23, 15, 0, 0,
+		time.FixedZone("CET", 3600)))
+	q.Del("b")
+
+	encoded := q.Encode()
+	assert(t,
+		"a=3&c=a%26b%3Dc%2Bd%3Be%20f/?:@%25&versionTime=2023-11-14T22:15:00Z",
+		encoded)
+
+	// the DID URL with encoded query is valid
+	did, err := ParseDID("did:example:123")
+	assert(t, nil, err)
+	did.SetQueryParams(q)
+	did, err = ParseDID(did.String())
+	assert(t, nil, err)
+	q2, err := did.QueryParams()
+	assert(t, nil, err)
+	assert(t, q, q2)
+
+	values, err := url.ParseQuery(encoded)
+	assert(t, nil, err)
+	assert(t, "a&b=c+d;e f/?:@%", values.Get("c"))
+}
+
+func TestQueryParams_Validate(t *testing.T) {
+	testCases := []struct {
+		query string
+		err   string
+	}{
+		{
+			query: "service=a&service=b",
+			err:   "invalid DID URL: service is used more than once",
+		},
+		{
+			query: "versionId=",
+			err:   "invalid DID URL: versionId is empty",
+		},
+		{
+			query: "versionTime=2023-11-14",
+			err:   `invalid DID URL: invalid versionTime: parsing time "2023-11-14" as "2006-01-02T15:04:05Z07:00": cannot parse "" as "T"`,
+		},
+		{
+			query: "relativeRef=%2Fa",
+			err:   "invalid DID URL: relativeRef is used without service",
+		},
+		{
+			query: "service=a&relativeRef=https%3A%2F%2Fexample.com",
+			err:   `invalid DID URL: relativeRef is not relative: "https://example.com"`,
+		},
+		{
+			query: "service=a&relativeRef=%25zz",
+			err:   `invalid DID URL: invalid relativeRef: parse "%zz": invalid URL escape "%zz"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseQueryParams(tc.query)
+			assert(t, nil, err)
+			err = q.Validate()
+			assert(t, true, errors.Is(err, ErrInvalidDIDURL))
+			assert(t, tc.err, err.Error())
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package w3c
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Representations of DID document
+const (
+	// ContentTypeDIDJSON is JSON representation of DID document
+	ContentTypeDIDJSON = "application/did+json"
+	// ContentTypeDIDLDJSON is JSON-LD representation of DID document
+	ContentTypeDIDLDJSON = "application/did+ld+json"
+)
+
+// Error codes of DID resolution metadata
+// https://www.w3.org/TR/did-spec-registries/#error
+const (
+	ErrorCodeInvalidDID                 = "invalidDid"
+	ErrorCodeInvalidDIDURL              = "invalidDidUrl"
+	ErrorCodeInvalidOptions             = "invalidOptions"
+	ErrorCodeNotFound                   = "notFound"
+	ErrorCodeRepresentationNotSupported = "representationNotSupported"
+	ErrorCodeMethodNotSupported         = "methodNotSupported"
+	ErrorCodeInternalError              = "internalError"
+)
+
+// ResolutionError is an error of DID resolution with error code of DID
+// resolution metadata
+type ResolutionError struct {
+	// Code is one of ErrorCode constants
+	Code string
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *ResolutionError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("DID resolution error: %v", e.Code)
+	}
+	return fmt.Sprintf("DID resolution error: %v: %v", e.Code, e.Err)
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// ResolutionOptions are DID resolution options
+// https://www.w3.org/TR/did-core/#did-resolution-options
+type ResolutionOptions struct {
+	// Accept is the requested representation of DID document, one of
+	// ContentType constants. JSON-LD representation is used if empty.
+	Accept string
+	// VersionID selects the version of DID document
+	VersionID string
+	// VersionTime selects the version of DID document valid at the time.
+	// Not set if zero.
+	VersionTime time.Time
+	// MethodOptions are options specific for DID method
+	MethodOptions map[string]interface{}
+}
+
+// DIDResolutionMetadata is metadata of DID resolution process
+// https://www.w3.org/TR/did-core/#did-resolution-metadata
+type DIDResolutionMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DIDDocumentMetadata is metadata of DID document
+// https://www.w3.org/TR/did-core/#did-document-metadata
+type DIDDocumentMetadata struct {
+	Created       string   `json:"created,omitempty"`
+	Updated       string   `json:"updated,omitempty"`
+	Deactivated   bool     `json:"deactivated,omitempty"`
+	NextUpdate    string   `json:"nextUpdate,omitempty"`
+	VersionID     string   `json:"versionId,omitempty"`
+	NextVersionID string   `json:"nextVersionId,omitempty"`
+	EquivalentID  []string `json:"equivalentId,omitempty"`
+	CanonicalID   string   `json:"canonicalId,omitempty"`
+}
+
+// ResolutionResult is a result of DID resolution
+type ResolutionResult struct {
+	DIDResolutionMetadata DIDResolutionMetadata `json:"didResolutionMetadata"`
+	DIDDocument           *DIDDocument          `json:"didDocument"`
+	DIDDocumentMetadata   DIDDocumentMetadata   `json:"didDocumentMetadata"`
+}
+
+// Resolver resolves DID to DID document. On failure it returns
+// *ResolutionError and the result with the error code set in
+// DIDResolutionMetadata.
+type Resolver interface {
+	Resolve(ctx context.Context, did DID,
+		opts ResolutionOptions) (ResolutionResult, error)
+}
+
+// FormatTime formats time as it is used in DID document metadata, i.e.
+// RFC 3339 in UTC with seconds precision
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// NewResolutionErrorResult returns the result and *ResolutionError with the
+// error code
+func NewResolutionErrorResult(code string, err error) (ResolutionResult,
+	error) {
+
+	return ResolutionResult{
+		DIDResolutionMetadata: DIDResolutionMetadata{Error: code},
+	}, &ResolutionError{Code: code, Err: err}
+}
+
+// MultiResolver is a Resolver that dispatches resolution by DID method to
+// registered resolvers
+type MultiResolver struct {
+	mu        sync.RWMutex
+	resolvers map[string]Resolver
+}
+
+// NewMultiResolver creates new MultiResolver without registered resolvers
+func NewMultiResolver() *MultiResolver {
+	return &MultiResolver{resolvers: make(map[string]Resolver)}
+}
+
+// Register sets the resolver for DID method. Previous resolver of the method
+// is replaced.
+func (r *MultiResolver) Register(method string, resolver Resolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolvers[method] = resolver
+}
+
+// Resolve resolves DID with the resolver of its method. It checks that did is
+// not a DID URL and that requested representation is supported.
+func (r *MultiResolver) Resolve(ctx context.Context, did DID,
+	opts ResolutionOptions) (ResolutionResult, error) {
+
+	if did.Method == "" || did.IsURL() {
+		return NewResolutionErrorResult(ErrorCodeInvalidDID,
+			fmt.Errorf("not a DID: %q", did.String()))
+	}
+
+	switch opts.Accept {
+	case "", ContentTypeDIDJSON, ContentTypeDIDLDJSON:
+	default:
+		return NewResolutionErrorResult(ErrorCodeRepresentationNotSupported,
+			fmt.Errorf("unsupported representation: %q", opts.Accept))
+	}
+
+	r.mu.RLock()
+	resolver, ok := r.resolvers[did.Method]
+	r.mu.RUnlock()
+	if !ok {
+		return NewResolutionErrorResult(ErrorCodeMethodNotSupported,
+			fmt.Errorf("no resolver for DID method %q", did.Method))
+	}
+
+	return resolver.Resolve(ctx, did, opts)
+}
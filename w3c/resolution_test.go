@@ -0,0 +1,70 @@
+package w3c
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type staticResolver struct {
+	doc *DIDDocument
+}
+
+func (r *staticResolver) Resolve(_ context.Context, did DID,
+	_ ResolutionOptions) (ResolutionResult, error) {
+
+	return ResolutionResult{
+		DIDResolutionMetadata: DIDResolutionMetadata{
+			ContentType: ContentTypeDIDJSON},
+		DIDDocument: r.doc,
+	}, nil
+}
+
+func TestMultiResolver(t *testing.T) {
+	doc := &DIDDocument{ID: DID{Method: "example", ID: "123"}}
+	r := NewMultiResolver()
+	r.Register("example", &staticResolver{doc: doc})
+
+	res, err := r.Resolve(context.Background(),
+		DID{Method: "example", ID: "123"}, ResolutionOptions{})
+	assert(t, nil, err)
+	assert(t, doc, res.DIDDocument)
+
+	testCases := []struct {
+		name string
+		did  DID
+		opts ResolutionOptions
+		code string
+		msg  string
+	}{
+		{
+			name: "unknown method",
+			did:  DID{Method: "other", ID: "123"},
+			code: ErrorCodeMethodNotSupported,
+			msg:  `DID resolution error: methodNotSupported: no resolver for DID method "other"`,
+		},
+		{
+			name: "DID URL",
+			did:  DID{Method: "example", ID: "123", Fragment: "key"},
+			code: ErrorCodeInvalidDID,
+			msg:  `DID resolution error: invalidDid: not a DID: "did:example:123#key"`,
+		},
+		{
+			name: "unsupported representation",
+			did:  DID{Method: "example", ID: "123"},
+			opts: ResolutionOptions{Accept: "text/html"},
+			code: ErrorCodeRepresentationNotSupported,
+			msg:  `DID resolution error: representationNotSupported: unsupported representation: "text/html"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := r.Resolve(context.Background(), tc.did, tc.opts)
+			var resErr *ResolutionError
+			assert(t, true, errors.As(err, &resErr))
+			assert(t, tc.code, resErr.Code)
+			assert(t, tc.msg, err.Error())
+			assert(t, tc.code, res.DIDResolutionMetadata.Error)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package w3c
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer. DID is stored as string.
+func (did DID) Value() (driver.Value, error) {
+	s := did.String()
+	if s == "" {
+		return nil, fmt.Errorf("can't store invalid DID: %+v", did)
+	}
+	return s, nil
+}
+
+// Scan implements sql.Scanner. It accepts string parsed by ParseDID.
+func (did *DID) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("can't scan %T into DID", src)
+	}
+	did2, err := ParseDID(s)
+	if err != nil {
+		return fmt.Errorf("can't scan DID: %w", err)
+	}
+	*did = *did2
+	return nil
+}
@@ -0,0 +1,46 @@
+package w3c
+
+import (
+	"testing"
+
+	"github.com/iden3/go-iden3-core/v2/internal/sqltest"
+)
+
+func TestDID_SQL(t *testing.T) {
+	db, err := sqltest.Open(t.Name())
+	assert(t, nil, err)
+	defer db.Close()
+
+	did, err := ParseDID("did:example:123:456?service=x")
+	assert(t, nil, err)
+	_, err = db.Exec("INSERT INTO t VALUES (?)", did)
+	assert(t, nil, err)
+
+	_, err = db.Exec("INSERT INTO t VALUES (?)", DID{Method: "example"})
+	assert(t, false, err == nil)
+
+	var did2 DID
+	err = db.QueryRow("SELECT * FROM t").Scan(&did2)
+	assert(t, nil, err)
+	assert(t, *did, did2)
+
+	var s string
+	err = db.QueryRow("SELECT * FROM t").Scan(&s)
+	assert(t, nil, err)
+	assert(t, "did:example:123:456?service=x", s)
+}
+
+func TestDID_Scan(t *testing.T) {
+	var did DID
+	assert(t, nil, did.Scan([]byte("did:example:123")))
+	assert(t, "did:example:123", did.String())
+
+	err := did.Scan(nil)
+	assert(t, "can't scan <nil> into DID", err.Error())
+	err = did.Scan(int64(1))
+	assert(t, "can't scan int64 into DID", err.Error())
+	err = did.Scan("did:example:12^3")
+	assert(t, "can't scan DID: byte is not ALPHA OR DIGIT OR '.' OR '-'",
+		err.Error())
+	assert(t, "did:example:123", did.String())
+}